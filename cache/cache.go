@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+// response publik yang disimpan di cache
+type Entry struct {
+	Status      int
+	ContentType string
+	Body        []byte
+	ETag        string
+	// updated_at terbaru dari konten group, kosong jika tidak diketahui (header Last-Modified tidak dikirim)
+	LastModified time.Time
+}
+
+type item struct {
+	key       string
+	entry     Entry
+	expiresAt time.Time
+}
+
+// cache LRU dengan TTL, key diawali nama group ("articles:/api/v1/articles")
+// sehingga satu group bisa dihapus sekaligus saat konten berubah.
+// Cache ada di memori tiap proses: Invalidate hanya menghapus cache di proses yang menangani perubahan,
+// instance lain tetap menyajikan response lama sampai CACHE_TTL habis
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List
+	versions map[string]uint64
+}
+
+// cache response publik, diisi oleh InitCache
+var Responses *Cache
+
+func New(capacity int, ttl time.Duration) *Cache {
+	return &Cache{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+		versions: map[string]uint64{},
+	}
+}
+
+func InitCache() {
+	Responses = New(config.GetEnvInt("CACHE_CAPACITY", 1000), config.GetEnvDuration("CACHE_TTL", 5*time.Minute))
+}
+
+func (c *Cache) Get(key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return Entry{}, false
+	}
+	it := element.Value.(*item)
+	if time.Now().After(it.expiresAt) {
+		c.remove(element)
+		return Entry{}, false
+	}
+	c.order.MoveToFront(element)
+	return it.entry, true
+}
+
+func (c *Cache) Set(key string, entry Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+	c.items[key] = c.order.PushFront(&item{key: key, entry: entry, expiresAt: time.Now().Add(c.ttl)})
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// hapus semua entry milik group dan naikkan versinya
+func (c *Cache) Invalidate(groups ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, group := range groups {
+		c.versions[group]++
+		for key, element := range c.items {
+			if strings.HasPrefix(key, group+":") {
+				c.remove(element)
+			}
+		}
+	}
+}
+
+// bertambah setiap group di-invalidate, untuk mendeteksi perubahan selama handler berjalan
+func (c *Cache) Version(group string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.versions[group]
+}
+
+func (c *Cache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*item).key)
+}
+
+// invalidasi cache response publik, aman dipanggil walau cache belum diinisialisasi
+func Invalidate(groups ...string) {
+	if Responses != nil {
+		Responses.Invalidate(groups...)
+	}
+}
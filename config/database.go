@@ -2,10 +2,12 @@ package config
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 var DB *sql.DB
@@ -15,15 +17,48 @@ func ConnectDB() {
 
 	connString := "user=postgres password=admin@2004 dbname=codetech host=127.0.0.1 port=5432 sslmode=disable"
 
-	DB, err = sql.Open("postgres", connString)
+	// setiap query otomatis jadi child span dari span request
+	DB, err = otelsql.Open("postgres", connString,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
-		log.Fatal("Error membuka koneksi:", err)
+		Fatal("Error membuka koneksi", "error", err)
 	}
 
-	err = DB.Ping()
-	if err != nil {
-		log.Fatal("Tidak bisa connect:", err)
+	// pengaturan pool koneksi
+	DB.SetMaxOpenConns(GetEnvInt("DB_MAX_OPEN_CONNS", 25))
+	DB.SetMaxIdleConns(GetEnvInt("DB_MAX_IDLE_CONNS", 10))
+	DB.SetConnMaxLifetime(GetEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute))
+	DB.SetConnMaxIdleTime(GetEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute))
+
+	// database bisa belum siap saat container baru jalan, coba ulang dengan backoff
+	retries := GetEnvInt("DB_CONNECT_RETRIES", 5)
+	backoff := GetEnvDuration("DB_CONNECT_BACKOFF", time.Second)
+	maxBackoff := GetEnvDuration("DB_CONNECT_MAX_BACKOFF", 30*time.Second)
+	for attempt := 0; ; attempt++ {
+		err = DB.Ping()
+		if err == nil {
+			break
+		}
+		if attempt >= retries {
+			Fatal("Tidak bisa connect", "error", err)
+		}
+
+		slog.Warn("Database belum siap", "attempt", attempt+1, "retries", retries, "retry_in", backoff.String(), "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxBackoff)
 	}
 
-	fmt.Println("Connected to SQL Server! ✅")
+	slog.Info("Connected to database")
+}
+
+// tutup koneksi database saat aplikasi berhenti
+func CloseDB() {
+	if DB == nil {
+		return
+	}
+	if err := DB.Close(); err != nil {
+		slog.Error("Gagal menutup koneksi database", "error", err)
+	}
 }
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// key AES-256 untuk data sensitif yang disimpan terenkripsi (secret TOTP)
+var EncryptionKey []byte
+
+// APP_ENCRYPTION_KEY berisi 32 byte acak dalam base64, contoh: openssl rand -base64 32
+func InitEncryptionKey() {
+	value := GetEnv("APP_ENCRYPTION_KEY", "")
+	if value == "" {
+		// key sementara hanya untuk local, secret 2FA yang tersimpan tidak bisa dibaca lagi setelah restart
+		if gin.Mode() == gin.ReleaseMode {
+			Fatal("APP_ENCRYPTION_KEY wajib diisi di release mode")
+		}
+		EncryptionKey = make([]byte, 32)
+		if _, err := rand.Read(EncryptionKey); err != nil {
+			Fatal("Gagal membuat encryption key", "error", err)
+		}
+		slog.Warn("APP_ENCRYPTION_KEY is not set, using an ephemeral key")
+		return
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(key) != 32 {
+		Fatal("APP_ENCRYPTION_KEY harus 32 byte dalam base64")
+	}
+	EncryptionKey = key
+}
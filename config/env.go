@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// ambil env, pakai nilai default jika kosong
+func GetEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func GetEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func GetEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// tanggal dengan format 2006-01-02, zero time jika kosong atau tidak valid
+func GetEnvDate(key, fallback string) time.Time {
+	value, err := time.Parse("2006-01-02", GetEnv(key, fallback))
+	if err != nil {
+		return time.Time{}
+	}
+	return value
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWTKey struct {
+	Id         string
+	PrivateKey crypto.Signer // nil jika hanya untuk verifikasi (key lama)
+	PublicKey  crypto.PublicKey
+}
+
+// key untuk sign token dan semua key yang masih diterima saat verifikasi
+var (
+	JWTSigningKey       *JWTKey
+	JWTVerificationKeys map[string]*JWTKey
+	JWTIssuer           string
+)
+
+// baca key dari JWT_KEYS_DIR, setiap file <kid>.pem berisi private key (RSA / Ed25519)
+// atau public key saja untuk key lama yang sudah tidak dipakai sign.
+// JWT_ACTIVE_KID menentukan key yang dipakai sign token baru.
+func InitJWT() {
+	JWTIssuer = GetEnv("JWT_ISSUER", "codetech")
+	JWTVerificationKeys = map[string]*JWTKey{}
+
+	dir := GetEnv("JWT_KEYS_DIR", "")
+	if dir == "" {
+		// key sementara hanya untuk local, di production token akan tidak berlaku setiap restart
+		if gin.Mode() == gin.ReleaseMode {
+			Fatal("JWT_KEYS_DIR wajib diisi di release mode")
+		}
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			Fatal("Gagal membuat JWT key", "error", err)
+		}
+		key := &JWTKey{Id: "dev", PrivateKey: private, PublicKey: private.Public()}
+		JWTSigningKey = key
+		JWTVerificationKeys[key.Id] = key
+		slog.Warn("JWT_KEYS_DIR is not set, using an ephemeral Ed25519 key")
+		return
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		Fatal("Gagal membaca JWT_KEYS_DIR", "error", err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		key, err := loadJWTKey(file)
+		if err != nil {
+			Fatal("Gagal membaca JWT key", "file", file, "error", err)
+		}
+		JWTVerificationKeys[key.Id] = key
+	}
+
+	activeKid := GetEnv("JWT_ACTIVE_KID", "")
+	key, ok := JWTVerificationKeys[activeKid]
+	if !ok || key.PrivateKey == nil {
+		Fatal("JWT_ACTIVE_KID tidak ditemukan atau bukan private key", "kid", activeKid)
+	}
+	JWTSigningKey = key
+
+	slog.Info("JWT keys loaded", "active", activeKid, "verification", len(JWTVerificationKeys))
+}
+
+func loadJWTKey(path string) (*JWTKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM")
+	}
+
+	key := &JWTKey{Id: strings.TrimSuffix(filepath.Base(path), ".pem")}
+
+	switch block.Type {
+	case "PRIVATE KEY", "RSA PRIVATE KEY":
+		var parsed interface{}
+		if block.Type == "RSA PRIVATE KEY" {
+			parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		} else {
+			parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch private := parsed.(type) {
+		case *rsa.PrivateKey:
+			key.PrivateKey = private
+		case ed25519.PrivateKey:
+			key.PrivateKey = private
+		default:
+			return nil, fmt.Errorf("unsupported private key type %T", parsed)
+		}
+		key.PublicKey = key.PrivateKey.Public()
+
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		switch parsed.(type) {
+		case *rsa.PublicKey, ed25519.PublicKey:
+			key.PublicKey = parsed
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", parsed)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+
+	return key, nil
+}
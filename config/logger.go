@@ -0,0 +1,29 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logger JSON untuk seluruh aplikasi, log.Printf lama ikut diarahkan ke sini
+func InitLogger() {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(GetEnv("LOG_LEVEL", "info"))); err != nil {
+		level = slog.LevelInfo
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler = slog.NewJSONHandler(os.Stdout, options)
+	if strings.EqualFold(GetEnv("LOG_FORMAT", "json"), "text") {
+		handler = slog.NewTextHandler(os.Stdout, options)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
+
+// catat error lalu hentikan aplikasi, pengganti log.Fatal
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// nil jika REDIS_URL tidak di-set
+var Redis *redis.Client
+
+func ConnectRedis() {
+	url := GetEnv("REDIS_URL", "")
+	if url == "" {
+		return
+	}
+
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		Fatal("REDIS_URL tidak valid", "error", err)
+	}
+	Redis = redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), GetEnvDuration("REDIS_CONNECT_TIMEOUT", 5*time.Second))
+	defer cancel()
+	if err := Redis.Ping(ctx).Err(); err != nil {
+		Fatal("Tidak bisa connect ke Redis", "error", err)
+	}
+
+	slog.Info("Connected to redis")
+}
+
+// tutup koneksi Redis saat aplikasi berhenti
+func CloseRedis() {
+	if Redis == nil {
+		return
+	}
+	if err := Redis.Close(); err != nil {
+		slog.Error("Gagal menutup koneksi Redis", "error", err)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// tracing OpenTelemetry, exporter dipilih lewat OTEL_TRACES_EXPORTER: otlp, stdout atau none (default).
+// exporter stdout menulis ke stderr agar tidak bercampur dengan log JSON di stdout.
+// endpoint OTLP memakai env standar OTEL_EXPORTER_OTLP_ENDPOINT.
+// mengembalikan fungsi shutdown untuk flush span saat aplikasi berhenti
+func InitTracing() func(context.Context) error {
+	// trace context W3C tetap diteruskan walau tracing dimatikan
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch GetEnv("OTEL_TRACES_EXPORTER", "none") {
+	case "otlp":
+		exporter, err = otlptracehttp.New(context.Background())
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr), stdouttrace.WithPrettyPrint())
+	default:
+		return func(context.Context) error { return nil }
+	}
+	if err != nil {
+		Fatal("Gagal membuat trace exporter", "error", err)
+	}
+
+	ratio, err := strconv.ParseFloat(GetEnv("OTEL_TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(GetEnv("OTEL_SERVICE_NAME", "backend-codetech")),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	slog.Info("Tracing ready", "exporter", GetEnv("OTEL_TRACES_EXPORTER", "none"), "sample_ratio", ratio)
+	return provider.Shutdown
+}
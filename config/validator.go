@@ -1,9 +1,33 @@
 package config
 
-import "github.com/go-playground/validator/v10"
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
 
 var Validate *validator.Validate
 
 func InitValidator() {
 	Validate = validator.New()
+	Validate.RegisterValidation("robots", validateRobots)
+}
+
+// direktif meta robots yang dikenal mesin pencari
+var robotsDirectives = map[string]bool{
+	"all": true, "none": true, "index": true, "noindex": true, "follow": true, "nofollow": true,
+	"noarchive": true, "nosnippet": true, "noimageindex": true, "notranslate": true,
+}
+
+// daftar direktif dipisah koma, contoh "noindex, nofollow" atau "max-snippet:50"
+func validateRobots(fl validator.FieldLevel) bool {
+	for _, directive := range strings.Split(fl.Field().String(), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		name, _, _ := strings.Cut(directive, ":")
+		if robotsDirectives[directive] || name == "max-snippet" || name == "max-image-preview" || name == "max-video-preview" {
+			continue
+		}
+		return false
+	}
+	return true
 }
@@ -0,0 +1,105 @@
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// dokumen editor berbasis block (format Editor.js)
+type document struct {
+	Blocks []block `json:"blocks"`
+}
+
+type block struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type blockData struct {
+	Text    string            `json:"text"`
+	Level   int               `json:"level"`
+	Style   string            `json:"style"`
+	Items   []json.RawMessage `json:"items"`
+	Caption string            `json:"caption"`
+	Code    string            `json:"code"`
+	Url     string            `json:"url"`
+	File    struct {
+		Url string `json:"url"`
+	} `json:"file"`
+}
+
+// render block editor menjadi HTML, teks inline dibiarkan karena disanitasi oleh Render
+func renderBlocks(raw string) (string, error) {
+	var doc document
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return "", fmt.Errorf("invalid blocks content: %w", err)
+	}
+
+	var out strings.Builder
+	for _, b := range doc.Blocks {
+		var data blockData
+		if len(b.Data) > 0 {
+			if err := json.Unmarshal(b.Data, &data); err != nil {
+				return "", fmt.Errorf("invalid %s block: %w", b.Type, err)
+			}
+		}
+
+		switch b.Type {
+		case "paragraph":
+			fmt.Fprintf(&out, "<p>%s</p>\n", data.Text)
+		case "header":
+			level := data.Level
+			if level < 1 || level > 6 {
+				level = 2
+			}
+			fmt.Fprintf(&out, "<h%d>%s</h%d>\n", level, data.Text, level)
+		case "list":
+			tag := "ul"
+			if data.Style == "ordered" {
+				tag = "ol"
+			}
+			out.WriteString("<" + tag + ">\n")
+			for _, item := range data.Items {
+				fmt.Fprintf(&out, "<li>%s</li>\n", listItem(item))
+			}
+			out.WriteString("</" + tag + ">\n")
+		case "image":
+			src := data.File.Url
+			if src == "" {
+				src = data.Url
+			}
+			fmt.Fprintf(&out, "<figure><img src=\"%s\" alt=\"%s\">", html.EscapeString(src), html.EscapeString(PlainText(data.Caption)))
+			if data.Caption != "" {
+				fmt.Fprintf(&out, "<figcaption>%s</figcaption>", data.Caption)
+			}
+			out.WriteString("</figure>\n")
+		case "quote":
+			fmt.Fprintf(&out, "<blockquote><p>%s</p>", data.Text)
+			if data.Caption != "" {
+				fmt.Fprintf(&out, "<p>%s</p>", data.Caption)
+			}
+			out.WriteString("</blockquote>\n")
+		case "code":
+			fmt.Fprintf(&out, "<pre><code>%s</code></pre>\n", html.EscapeString(data.Code))
+		case "delimiter":
+			out.WriteString("<hr>\n")
+		}
+		// block lain (embed, raw, dll) diabaikan
+	}
+	return out.String(), nil
+}
+
+// item list bisa berupa string atau {"content": "..."} untuk list bertingkat
+func listItem(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var nested struct {
+		Content string `json:"content"`
+	}
+	json.Unmarshal(raw, &nested)
+	return nested.Content
+}
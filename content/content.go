@@ -0,0 +1,51 @@
+package content
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// format isi artikel yang diterima dari editor
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatBlocks   = "blocks"
+)
+
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// ubah isi mentah sesuai formatnya menjadi HTML yang sudah disanitasi, heading diberi anchor id
+func Render(format, raw string) (string, error) {
+	var rendered string
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(raw), &buf); err != nil {
+			return "", err
+		}
+		rendered = buf.String()
+	case FormatBlocks:
+		html, err := renderBlocks(raw)
+		if err != nil {
+			return "", err
+		}
+		rendered = html
+	case FormatHTML, "":
+		rendered = raw
+	default:
+		return "", fmt.Errorf("unknown content format %q", format)
+	}
+	return addHeadingIds(Sanitize(rendered)), nil
+}
+
+// isi mentah yang disimpan: HTML ikut disanitasi, markdown dan blocks disimpan apa adanya
+// karena hasil render-nya selalu disanitasi
+func Normalize(format, raw string) string {
+	if format == FormatHTML || format == "" {
+		return Sanitize(raw)
+	}
+	return raw
+}
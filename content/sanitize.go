@@ -0,0 +1,61 @@
+package content
+
+import (
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// gambar di isi artikel hanya boleh berasal dari folder uploads
+var uploadImage = regexp.MustCompile(`^/uploads/[A-Za-z0-9._-]+$`)
+
+var imageSrc = regexp.MustCompile(`<img[^>]*\ssrc="(/uploads/[A-Za-z0-9._-]+)"`)
+
+// allow-list tag dan atribut yang aman ditampilkan di situs publik
+var policy = newPolicy()
+
+// buang semua tag, hanya teks yang tersisa
+var stripPolicy = bluemonday.StrictPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowElements(
+		"p", "br", "hr", "h1", "h2", "h3", "h4", "h5", "h6",
+		"strong", "b", "em", "i", "u", "s", "del", "mark", "sub", "sup",
+		"blockquote", "ul", "ol", "li", "pre", "code",
+		"figure", "figcaption", "table", "thead", "tbody", "tr", "th", "td",
+	)
+
+	p.AllowStandardURLs()
+	p.AllowAttrs("href", "title").OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	p.AllowAttrs("src").Matching(uploadImage).OnElements("img")
+	p.AllowAttrs("alt", "title").Matching(bluemonday.Paragraph).OnElements("img")
+	p.AllowAttrs("width", "height").Matching(bluemonday.NumberOrPercent).OnElements("img")
+
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[a-z0-9-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[A-Za-z0-9_+-]+$`)).OnElements("code")
+	p.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	p.AllowAttrs("colspan", "rowspan").Matching(bluemonday.Integer).OnElements("td", "th")
+	return p
+}
+
+// buang tag, atribut dan url yang tidak ada di allow-list
+func Sanitize(html string) string {
+	return policy.Sanitize(html)
+}
+
+// path file uploads yang dipakai sebagai gambar di HTML hasil render
+func Images(html string) []string {
+	images := []string{}
+	seen := map[string]bool{}
+	for _, match := range imageSrc.FindAllStringSubmatch(html, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			images = append(images, match[1])
+		}
+	}
+	return images
+}
@@ -0,0 +1,92 @@
+package content
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"allowed markup", `<p>Hello <strong>world</strong></p>`, `<p>Hello <strong>world</strong></p>`},
+		{"script removed", `<script>alert(1)</script><p>x</p>`, `<p>x</p>`},
+		{"event handler removed", `<p onclick="evil()">x</p>`, `<p>x</p>`},
+		{"inline style removed", `<p style="color:red">x</p>`, `<p>x</p>`},
+		{"iframe removed", `<iframe src="https://example.com"></iframe>`, ``},
+		{"javascript url removed", `<a href="javascript:alert(1)">x</a>`, `x`},
+		{"external link", `<a href="https://example.com">x</a>`, `<a href="https://example.com" rel="nofollow noopener" target="_blank">x</a>`},
+		{"relative link", `<a href="/articles/go">x</a>`, `<a href="/articles/go" rel="nofollow">x</a>`},
+		{"upload image", `<img src="/uploads/a.png" alt="A">`, `<img src="/uploads/a.png" alt="A">`},
+		{"external image", `<img src="https://example.com/a.png">`, ``},
+		{"path traversal image", `<img src="/uploads/../secret.png">`, ``},
+		{"heading id", `<h2 id="intro">Intro</h2>`, `<h2 id="intro">Intro</h2>`},
+		{"invalid heading id", `<h2 id="Bad Id">Intro</h2>`, `<h2>Intro</h2>`},
+		{"code language class", `<code class="language-go">x</code>`, `<code class="language-go">x</code>`},
+		{"other code class", `<code class="evil">x</code>`, `<code>x</code>`},
+		{"table cell span", `<td colspan="2">x</td>`, `<td colspan="2">x</td>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"no images", `<p>x</p>`, []string{}},
+		{"single image", `<p><img src="/uploads/a.png" alt="a"></p>`, []string{"/uploads/a.png"}},
+		{"duplicates removed", `<img src="/uploads/a.png"><img src="/uploads/b.jpg"><img src="/uploads/a.png">`, []string{"/uploads/a.png", "/uploads/b.jpg"}},
+		{"non upload ignored", `<img src="https://example.com/a.png">`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Images(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Images(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderSanitizesEveryFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		in     string
+		want   string
+	}{
+		{"markdown raw html", FormatMarkdown, "# Title\n\n<script>alert(1)</script>", `<h1 id="title">Title</h1>` + "\n\n"},
+		{"markdown external image", FormatMarkdown, "![a](https://example.com/a.png)", `<p><img alt="a"></p>` + "\n"},
+		{"html", FormatHTML, `<p onclick="x()">Hi</p>`, `<p>Hi</p>`},
+		{"blocks", FormatBlocks, `{"blocks":[{"type":"paragraph","data":{"text":"<b>Hi</b><script>x</script>"}}]}`, `<p><b>Hi</b></p>` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, tt.in)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%s, %q) = %q, want %q", tt.format, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if _, err := Render("docx", "x"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
@@ -0,0 +1,96 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gosimple/slug"
+)
+
+// kecepatan baca rata-rata untuk estimasi reading time
+const WordsPerMinute = 200
+
+// heading untuk daftar isi, id dipakai sebagai anchor (#id)
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	Id    string `json:"id"`
+}
+
+var headingTag = regexp.MustCompile(`(?s)<h([1-6])(?:\s+id="[^"]*")?>(.*?)</h[1-6]>`)
+
+var headingWithId = regexp.MustCompile(`(?s)<h([1-6]) id="([^"]*)">(.*?)</h[1-6]>`)
+
+// beri id unik pada setiap heading berdasarkan teksnya
+func addHeadingIds(rendered string) string {
+	used := map[string]int{}
+	return headingTag.ReplaceAllStringFunc(rendered, func(tag string) string {
+		match := headingTag.FindStringSubmatch(tag)
+		id := slug.Make(PlainText(match[2]))
+		if id == "" {
+			id = "section"
+		}
+		used[id]++
+		if used[id] > 1 {
+			id = fmt.Sprintf("%s-%d", id, used[id])
+		}
+		return fmt.Sprintf(`<h%s id="%s">%s</h%s>`, match[1], id, match[2], match[1])
+	})
+}
+
+// daftar isi dari HTML hasil Render
+func TableOfContents(rendered string) []Heading {
+	toc := []Heading{}
+	for _, match := range headingWithId.FindAllStringSubmatch(rendered, -1) {
+		toc = append(toc, Heading{Level: int(match[1][0] - '0'), Id: match[2], Text: PlainText(match[3])})
+	}
+	return toc
+}
+
+// teks tanpa markup, spasi berlebih dirapikan
+func PlainText(s string) string {
+	// tag block diberi spasi agar kata antar paragraf tidak menempel
+	s = strings.NewReplacer("<br>", " ", "</p>", " </p>", "</li>", " </li>", "</h", " </h", "</td>", " </td>").Replace(s)
+	return strings.Join(strings.Fields(html.UnescapeString(stripPolicy.Sanitize(s))), " ")
+}
+
+func WordCount(rendered string) int {
+	return len(strings.Fields(PlainText(rendered)))
+}
+
+// estimasi menit membaca, minimal 1 menit untuk artikel yang ada isinya
+func ReadingTime(words int) int {
+	if words == 0 {
+		return 0
+	}
+	return int(math.Ceil(float64(words) / WordsPerMinute))
+}
+
+var paragraphTag = regexp.MustCompile(`(?s)<p>(.*?)</p>`)
+
+// ringkasan dari paragraf (heading diabaikan), dipotong di batas kata maksimal maxChars karakter
+func Excerpt(rendered string, maxChars int) string {
+	paragraphs := []string{}
+	for _, match := range paragraphTag.FindAllStringSubmatch(rendered, -1) {
+		paragraphs = append(paragraphs, match[1])
+	}
+	if len(paragraphs) > 0 {
+		rendered = strings.Join(paragraphs, " ")
+	}
+
+	text := PlainText(rendered)
+	if utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:maxChars])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-") + "…"
+}
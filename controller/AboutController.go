@@ -11,6 +11,7 @@ import (
 
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -18,9 +19,10 @@ import (
 
 // getAllDate
 func GetAllAbout(c *gin.Context) {
+	ctx := c.Request.Context()
 	var about model.About
 
-	err := config.DB.QueryRow("SELECT id, title, description, image, created_at, updated_at FROM abouts").Scan(
+	err := config.DB.QueryRowContext(ctx, "SELECT id, title, description, image, created_at, updated_at FROM abouts").Scan(
 		&about.Id, &about.Title, &about.Description, &about.Image, &about.CreatedAt, &about.UpdatedAt,
 	)
 
@@ -29,7 +31,7 @@ func GetAllAbout(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "No data found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -40,6 +42,7 @@ func GetAllAbout(c *gin.Context) {
 
 // create data
 func CreateAbout(c *gin.Context) {
+	ctx := c.Request.Context()
 	title := c.PostForm("title")
 	description := c.PostForm("description")
 
@@ -69,29 +72,34 @@ func CreateAbout(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
 	// check apakah sudah ada data di database atau belum, jika sudah maka tidak bisa menambahkan data lagi
 	var about model.About
-	err = config.DB.QueryRow("SELECT id FROM abouts").Scan(&about.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM abouts").Scan(&about.Id)
 	if err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Data already exists"})
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
 		INSERT INTO abouts (title, description, image, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5)
-	`, title, description, "/uploads/"+filename, time.Now(), time.Now())
+		RETURNING id
+	`, title, description, "/uploads/"+filename, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -99,6 +107,7 @@ func CreateAbout(c *gin.Context) {
 
 // update
 func UpdateAbout(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -128,12 +137,12 @@ func UpdateAbout(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan image lama
 	var oldImage string
-	err = config.DB.QueryRow("SELECT image FROM abouts WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
+	err = config.DB.QueryRowContext(ctx, "SELECT image FROM abouts WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "About not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing about", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing about", err)
 		return
 	}
 
@@ -145,8 +154,8 @@ func UpdateAbout(c *gin.Context) {
 		os.MkdirAll("uploads", os.ModePerm)
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := "uploads/" + filename
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 
@@ -155,21 +164,21 @@ func UpdateAbout(c *gin.Context) {
 			_, oldFile := filepath.Split(oldImage)
 			oldFilePath := filepath.Join("uploads", oldFile)
 			if _, err := os.Stat(oldFilePath); err == nil {
-				os.Remove(oldFilePath)
+				utils.RemoveUpload(c, oldFilePath)
 			}
 		}
 
 		imagePath = "/uploads/" + filename
 	}
 
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		UPDATE abouts
 		SET title = $1, description = $2, image = $3, updated_at = $4
 		WHERE id = $5
 	`, title, description, imagePath, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
@@ -180,6 +189,7 @@ func UpdateAbout(c *gin.Context) {
 
 // delete
 func DeleteAbout(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -189,35 +199,34 @@ func DeleteAbout(c *gin.Context) {
 
 	var about model.About
 	// Langsung ambil id dan image dalam satu query
-	err = config.DB.QueryRow("SELECT id, image FROM abouts WHERE id = $1", sql.Named("p1", id)).Scan(&about.Id, &about.Image)
+	err = config.DB.QueryRowContext(ctx, "SELECT id, image FROM abouts WHERE id = $1", sql.Named("p1", id)).Scan(&about.Id, &about.Image)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 		}
 		return
 	}
 
+	// Hapus data dari database
+	_, err = config.DB.ExecContext(ctx, "DELETE FROM abouts WHERE id = $1", sql.Named("p1", id))
+	if err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
+		return
+	}
+
 	// Hapus file gambar jika ada
 	if about.Image != "" {
 		_, imageFile := filepath.Split(about.Image)
 		imagePath := filepath.Join("uploads", imageFile)
 		if _, err := os.Stat(imagePath); err == nil {
-			if err := os.Remove(imagePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image", "detail": err.Error()})
-				return
+			if err := utils.RemoveUpload(c, imagePath); err != nil {
+				utils.Logger(c).Error("Failed to delete image", "error", err, "path", imagePath)
 			}
 		}
 	}
 
-	// Hapus data dari database
-	_, err = config.DB.Exec("DELETE FROM abouts WHERE id = $1", sql.Named("p1", id))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+// get all api key (tanpa key asli)
+func GetAllApiKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	if !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage API keys"})
+		return
+	}
+
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT id, name, prefix, scopes, created_by, expires_at, last_used_at, revoked_at, created_at
+		FROM api_keys
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+	defer rows.Close()
+
+	apiKeys := []model.ApiKey{}
+	for rows.Next() {
+		var apiKey model.ApiKey
+		if err := rows.Scan(&apiKey.Id, &apiKey.Name, &apiKey.Prefix, pq.Array(&apiKey.Scopes), &apiKey.CreatedBy, &apiKey.ExpiresAt, &apiKey.LastUsedAt, &apiKey.RevokedAt, &apiKey.CreatedAt); err != nil {
+			utils.ServerError(c, "Failed to fetch data", err)
+			return
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": apiKeys,
+	})
+}
+
+// create api key, key asli hanya ditampilkan sekali
+func CreateApiKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	if !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage API keys"})
+		return
+	}
+
+	var req model.ApiKeyRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		if !utils.ValidApiKeyScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scope: " + scope})
+			return
+		}
+	}
+
+	// tanggal dikirim apa adanya dan di-cast di database, sehingga awal hari mengikuti zona waktu
+	// database yang sama dengan NOW() di AuthMiddleware
+	var expiresAt sql.NullString
+	if req.ExpiresAt != "" {
+		date, _ := time.Parse("2006-01-02", req.ExpiresAt)
+		if !date.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Expiry date must be in the future"})
+			return
+		}
+		expiresAt = sql.NullString{String: req.ExpiresAt, Valid: true}
+	}
+
+	secret, err := utils.GenerateToken(24)
+	if err != nil {
+		utils.ServerError(c, "Failed to generate API key", err)
+		return
+	}
+	key := utils.ApiKeyPrefix + secret
+	prefix := key[:len(utils.ApiKeyPrefix)+6]
+
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6::date, $7)
+		RETURNING id
+	`, req.Name, prefix, utils.HashToken(key), pq.Array(req.Scopes), c.GetInt("user_id"), expiresAt, time.Now()).Scan(&newId)
+
+	if err != nil {
+		utils.ServerError(c, "Failed to insert data", err)
+		return
+	}
+
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "API key created successfully, store it now because it will not be shown again",
+		"data": gin.H{
+			"id":     newId,
+			"key":    key,
+			"prefix": prefix,
+			"scopes": req.Scopes,
+		},
+	})
+}
+
+// revoke api key
+func RevokeApiKey(c *gin.Context) {
+	ctx := c.Request.Context()
+	if !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage API keys"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var apiKeyId int
+	err = config.DB.QueryRowContext(ctx, `
+		UPDATE api_keys SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL
+		RETURNING id
+	`, time.Now(), id).Scan(&apiKeyId)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Failed to revoke API key", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "revoke")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API key revoked successfully",
+	})
+}
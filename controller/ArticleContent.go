@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/content"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+)
+
+var errArticleImageNotFound = errors.New("embedded image was not uploaded as an article image")
+
+// isi artikel yang sudah dirender beserta field turunannya
+type articleContent struct {
+	Description string
+	HTML        string
+	Toc         []content.Heading
+	Images      []string
+	WordCount   int
+	ReadingTime int
+}
+
+// respon 400 untuk isi artikel yang tidak bisa dirender
+func invalidArticleContent(c *gin.Context, err error) {
+	c.Error(err)
+	if errors.Is(err, errArticleImageNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Embedded images must be uploaded first"})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content"})
+}
+
+// render isi artikel beserta daftar gambar yang dipakai
+func renderArticleContent(format, description string) (articleContent, error) {
+	rendered, err := content.Render(format, description)
+	if err != nil {
+		return articleContent{}, err
+	}
+
+	images := content.Images(rendered)
+	words := content.WordCount(rendered)
+	return articleContent{
+		Description: content.Normalize(format, description),
+		HTML:        rendered,
+		Toc:         content.TableOfContents(rendered),
+		Images:      images,
+		WordCount:   words,
+		ReadingTime: content.ReadingTime(words),
+	}, nil
+}
+
+// gambar di isi artikel harus diupload lewat endpoint gambar artikel, atau sudah dipakai artikel ini sebelumnya
+func checkArticleImages(ctx context.Context, articleId int, images []string) error {
+	for _, image := range images {
+		var tracked bool
+		err := config.DB.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM article_uploads WHERE path = $1)
+				OR EXISTS(SELECT 1 FROM article_media WHERE article_id = $2 AND path = $1)
+		`, image, articleId).Scan(&tracked)
+		if err != nil {
+			return err
+		}
+		if !tracked {
+			return fmt.Errorf("%w: %s", errArticleImageNotFound, image)
+		}
+		if _, err := os.Stat(filepath.Join("uploads", filepath.Base(image))); err != nil {
+			return fmt.Errorf("%w: %s", errArticleImageNotFound, image)
+		}
+	}
+	return nil
+}
+
+// excerpt manual dibersihkan dari markup, jika kosong dibuat dari isi artikel
+func articleExcerpt(manual, rendered string) (string, bool) {
+	if excerpt := content.PlainText(manual); excerpt != "" {
+		return excerpt, true
+	}
+	return content.Excerpt(rendered, config.GetEnvInt("ARTICLE_EXCERPT_LENGTH", 200)), false
+}
+
+// artikel lama yang belum pernah disimpan ulang dihitung saat dibaca
+func fillLegacyArticle(article *model.ResponseArticle) {
+	// isi mentah lama belum pernah disanitasi
+	article.Description = content.Normalize(article.ContentFormat, article.Description)
+
+	rendered, err := content.Render(article.ContentFormat, article.Description)
+	if err != nil {
+		return
+	}
+	article.ContentHTML = rendered
+	article.Toc = content.TableOfContents(rendered)
+	article.WordCount = content.WordCount(rendered)
+	article.ReadingTime = content.ReadingTime(article.WordCount)
+	if article.Excerpt == "" {
+		article.Excerpt, _ = articleExcerpt("", rendered)
+	}
+}
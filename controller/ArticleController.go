@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -9,51 +12,70 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/content"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/seo"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/gosimple/slug"
+	"github.com/lib/pq"
 )
 
-// get all article
+// get all article, hanya excerpt tanpa isi lengkap
 func GetAllArticle(c *gin.Context) {
-	var article []model.ResponseArticle
+	ctx := c.Request.Context()
+	article, err := fetchArticles(ctx, false, "", "")
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
 
-	rows, err := config.DB.Query(`
-		SELECT a.id, a.title, a.slug, a.description, a.thumbnail, a.views, a.created_at, a.updated_at, u.name, c.category
-		FROM articles a
-		JOIN users u ON a.user_id = u.id
-		JOIN category_articles c ON a.category_id = c.id
-	`)
+	c.JSON(http.StatusOK, gin.H{
+		"data": article,
+	})
+}
+
+// get all article untuk admin, termasuk isi lengkap untuk editor
+func GetAllArticleAdmin(c *gin.Context) {
+	ctx := c.Request.Context()
+	article, err := fetchArticles(ctx, true, "", "")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
-	if rows == nil {
-		c.JSON(http.StatusOK, gin.H{"message": "No data found"})
+	c.JSON(http.StatusOK, gin.H{
+		"data": article,
+	})
+}
+
+// detail artikel berdasarkan slug
+func GetArticleBySlug(c *gin.Context) {
+	ctx := c.Request.Context()
+	article, err := fetchArticles(ctx, true, "a.slug = $1", "", c.Param("slug"))
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
 	}
 
-	for rows.Next() {
-		var art model.ResponseArticle
-		if err := rows.Scan(&art.Id, &art.Title, &art.Slug, &art.Description, &art.Thumbnail, &art.Views, &art.CreatedAt, &art.UpdatedAt, &art.User, &art.Category); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
-			return
-		}
-		article = append(article, art)
+	if len(article) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": article,
+		"data": article[0],
 	})
 }
 
 // create data
 func CreateArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	title := c.PostForm("title")
-	user := c.PostForm("user_id")
 	category := c.PostForm("category_id")
 	description := c.PostForm("description")
 
@@ -74,6 +96,42 @@ func CreateArticle(c *gin.Context) {
 		return
 	}
 
+	// penulis diambil dari user yang login, bukan dari form
+	user := c.GetInt("user_id")
+	if req.AuthorId != 0 && req.AuthorId != user {
+		if !utils.IsPrivileged(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to set another author"})
+			return
+		}
+		user = req.AuthorId
+	}
+
+	// isi artikel dirender dan disanitasi sebelum disimpan
+	format := req.ContentFormat
+	if format == "" {
+		format = content.FormatHTML
+	}
+	body, err := renderArticleContent(format, description)
+	if err != nil {
+		invalidArticleContent(c, err)
+		return
+	}
+	if err := checkArticleImages(ctx, 0, body.Images); err != nil {
+		if errors.Is(err, errArticleImageNotFound) {
+			invalidArticleContent(c, err)
+		} else {
+			utils.ServerError(c, "Database error", err)
+		}
+		return
+	}
+	if err := checkArticleAuthors(ctx, user, req.CoAuthors); err != nil {
+		articleAuthorsError(c, err)
+		return
+	}
+	excerpt, excerptManual := articleExcerpt(req.Excerpt, body.HTML)
+	toc, _ := json.Marshal(body.Toc)
+	meta := seoFromForm(c, model.Seo{})
+
 	file, err := c.FormFile("thumbnail")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Thumbnail is required"})
@@ -83,23 +141,62 @@ func CreateArticle(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
 	thumbnail := "/uploads/" + filename
 
-	_, err = config.DB.Exec(`
-		INSERT INTO articles (title, slug, user_id, category_id, description, thumbnail, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, title, slug.Make(title), user, category, description, thumbnail, time.Now(), time.Now())
+	// thumbnail yang sudah di-upload dihapus lagi jika data gagal disimpan
+	failed := func(msg string, err error) {
+		utils.RemoveUpload(c, savePath)
+		utils.ServerError(c, msg, err)
+	}
+
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		failed("Database error", err)
+		return
+	}
+	defer tx.Rollback()
+
+	var newId int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO articles (title, slug, user_id, category_id, description, content_format, content_html,
+			excerpt, excerpt_manual, word_count, reading_time, toc, thumbnail,
+			meta_title, meta_description, canonical_url, og_image, robots, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		RETURNING id
+	`, title, slug.Make(title), user, category, body.Description, format, body.HTML,
+		excerpt, excerptManual, body.WordCount, body.ReadingTime, toc, thumbnail,
+		meta.MetaTitle, meta.MetaDescription, meta.CanonicalUrl, meta.OgImage, meta.Robots, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		failed("Failed to insert data", err)
+		return
+	}
+
+	if err := saveArticleCoAuthors(ctx, tx, newId, user, req.CoAuthors); err != nil {
+		failed("Failed to insert data", err)
 		return
 	}
 
+	if _, err := saveArticleMedia(ctx, tx, newId, body.Images); err != nil {
+		failed("Failed to insert data", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		failed("Failed to insert data", err)
+		return
+	}
+
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	cache.Invalidate("articles", "sitemap")
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -107,9 +204,9 @@ func CreateArticle(c *gin.Context) {
 
 // update data
 func UpdateArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	title := c.PostForm("title")
-	user := c.PostForm("user_id")
 	category := c.PostForm("category_id")
 	description := c.PostForm("description")
 
@@ -138,64 +235,191 @@ func UpdateArticle(c *gin.Context) {
 
 	// Ambil data artikel termasuk thumbnail
 	var article model.Article
-	err = config.DB.QueryRow(
-		"SELECT id, thumbnail FROM articles WHERE id = $1",
-		sql.Named("p1", id),
-	).Scan(&article.Id, &article.Thumbnail)
+	err = config.DB.QueryRowContext(ctx,
+		`SELECT id, user_id, thumbnail, content_format, excerpt, excerpt_manual,
+			meta_title, meta_description, canonical_url, og_image, robots
+		FROM articles WHERE id = $1`,
+		id,
+	).Scan(&article.Id, &article.UserId, &article.Thumbnail, &article.ContentFormat, &article.Excerpt, &article.ExcerptManual,
+		&article.Seo.MetaTitle, &article.Seo.MetaDescription, &article.Seo.CanonicalUrl, &article.Seo.OgImage, &article.Seo.Robots)
 
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		utils.ServerError(c, "Database error", err)
 		return
 	}
 
+	// perubahan penulis / co-author hanya untuk role privileged
+	user := article.UserId
+	if req.AuthorId != 0 && req.AuthorId != article.UserId {
+		if !utils.IsPrivileged(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change the author"})
+			return
+		}
+		user = req.AuthorId
+	}
+
+	_, replaceCoAuthors := c.GetPostFormArray("co_authors")
+	if replaceCoAuthors && !utils.IsPrivileged(c) {
+		current, err := getArticleCoAuthors(ctx, []int{id})
+		if err != nil {
+			utils.ServerError(c, "Database error", err)
+			return
+		}
+		if !sameCoAuthors(current[id], normalizeCoAuthors(user, req.CoAuthors)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change the co-authors"})
+			return
+		}
+		replaceCoAuthors = false
+	}
+
+	// format tetap yang lama jika tidak dikirim
+	format := req.ContentFormat
+	if format == "" {
+		format = article.ContentFormat
+	}
+	body, err := renderArticleContent(format, description)
+	if err != nil {
+		invalidArticleContent(c, err)
+		return
+	}
+	if err := checkArticleImages(ctx, id, body.Images); err != nil {
+		if errors.Is(err, errArticleImageNotFound) {
+			invalidArticleContent(c, err)
+		} else {
+			utils.ServerError(c, "Database error", err)
+		}
+		return
+	}
+
+	if replaceCoAuthors || user != article.UserId {
+		coAuthors := []int{}
+		if replaceCoAuthors {
+			coAuthors = req.CoAuthors
+		}
+		if err := checkArticleAuthors(ctx, user, coAuthors); err != nil {
+			articleAuthorsError(c, err)
+			return
+		}
+	}
+
+	// excerpt manual dipertahankan jika field excerpt tidak dikirim
+	manualExcerpt := req.Excerpt
+	if _, sent := c.GetPostForm("excerpt"); !sent && article.ExcerptManual {
+		manualExcerpt = article.Excerpt
+	}
+	excerpt, excerptManual := articleExcerpt(manualExcerpt, body.HTML)
+	toc, _ := json.Marshal(body.Toc)
+	meta := seoFromForm(c, article.Seo)
+
 	// Default thumbnail tetap yang lama
 	thumbnail := article.Thumbnail
 
-	// Cek apakah ada file baru di-upload
+	// Cek apakah ada file baru di-upload, file lama baru dihapus setelah commit
+	var newUpload string
 	file, err := c.FormFile("thumbnail")
 	if err == nil {
 		// Upload file baru
 		os.MkdirAll("uploads", os.ModePerm)
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
-		savePath := filepath.Join("uploads", filename)
+		newUpload = filepath.Join("uploads", filename)
 
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, newUpload); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 
-		// Hapus file lama
-		if article.Thumbnail != "" {
-			oldFilePath := filepath.Join("uploads", filepath.Base(article.Thumbnail))
-			if _, err := os.Stat(oldFilePath); err == nil {
-				os.Remove(oldFilePath)
-			}
-		}
-
 		// Set thumbnail baru
 		thumbnail = "/uploads/" + filename
 	}
 
+	// thumbnail baru dihapus lagi jika data gagal disimpan
+	failed := func(msg string, err error) {
+		if newUpload != "" {
+			utils.RemoveUpload(c, newUpload)
+		}
+		utils.ServerError(c, msg, err)
+	}
+
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		failed("Database error", err)
+		return
+	}
+	defer tx.Rollback()
+
 	// Update database
-	_, err = config.DB.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		UPDATE articles
-		SET title = $1, slug = $2, user_id = $3, category_id = $4, description = $5, thumbnail = $6, updated_at = $7
-		WHERE id = $8
-	`, title, slug.Make(title), user, category, description, thumbnail, time.Now(), id)
+		SET title = $1, slug = $2, user_id = $3, category_id = $4, description = $5, content_format = $6, content_html = $7,
+			excerpt = $8, excerpt_manual = $9, word_count = $10, reading_time = $11, toc = $12, thumbnail = $13, updated_at = $14,
+			meta_title = $15, meta_description = $16, canonical_url = $17, og_image = $18, robots = $19
+		WHERE id = $20
+	`, title, slug.Make(title), user, category, body.Description, format, body.HTML,
+		excerpt, excerptManual, body.WordCount, body.ReadingTime, toc, thumbnail, time.Now(),
+		meta.MetaTitle, meta.MetaDescription, meta.CanonicalUrl, meta.OgImage, meta.Robots, id)
+
+	if err != nil {
+		failed("Failed to update data", err)
+		return
+	}
+
+	if replaceCoAuthors || user != article.UserId {
+		coAuthors := req.CoAuthors
+		if !replaceCoAuthors {
+			// penulis utama berubah, co-author lama tetap dipertahankan (dibaca sebelum dihapus)
+			current, err := getArticleCoAuthors(ctx, []int{id})
+			if err != nil {
+				failed("Database error", err)
+				return
+			}
+			coAuthors = []int{}
+			for _, author := range current[id] {
+				coAuthors = append(coAuthors, author.Id)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM article_authors WHERE article_id = $1", id); err != nil {
+			failed("Failed to update data", err)
+			return
+		}
 
+		if err := saveArticleCoAuthors(ctx, tx, id, user, coAuthors); err != nil {
+			failed("Failed to update data", err)
+			return
+		}
+	}
+
+	removedMedia, err := saveArticleMedia(ctx, tx, id, body.Images)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		failed("Failed to update data", err)
 		return
 	}
 
+	if err := tx.Commit(); err != nil {
+		failed("Failed to update data", err)
+		return
+	}
+
+	// Hapus file lama setelah data tersimpan
+	if newUpload != "" && article.Thumbnail != "" {
+		oldFilePath := filepath.Join("uploads", filepath.Base(article.Thumbnail))
+		if err := utils.RemoveUpload(c, oldFilePath); err != nil && !os.IsNotExist(err) {
+			utils.Logger(c).Error("Failed to delete thumbnail", "error", err)
+		}
+	}
+	removeUnusedMedia(c, removedMedia)
+
+	cache.Invalidate("articles", "sitemap")
+
 	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
 }
 
 // delete data
 func DeleteArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -205,25 +429,54 @@ func DeleteArticle(c *gin.Context) {
 
 	// check database
 	var article model.Article
-	err = config.DB.QueryRow("SELECT id FROM articles WHERE id = $1", sql.Named("p1", id)).Scan(&article.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM articles WHERE id = $1", sql.Named("p1", id)).Scan(&article.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	}
 
-	// delete file lama jika ada
 	var oldImage string
-	err = config.DB.QueryRow("SELECT thumbnail FROM articles WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
-	if err == nil && oldImage != "" {
-		_, filename := filepath.Split(oldImage)
-		os.Remove("uploads/" + filename)
+	err = config.DB.QueryRowContext(ctx, "SELECT thumbnail FROM articles WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
 	}
 
-	_, err = config.DB.Exec("DELETE FROM articles WHERE id = $1", sql.Named("p1", id))
+	tx, err := config.DB.BeginTx(ctx, nil)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		utils.ServerError(c, "Database error", err)
 		return
 	}
+	defer tx.Rollback()
+
+	// gambar di isi artikel ikut dihapus jika tidak dipakai artikel lain
+	removedMedia, err := saveArticleMedia(ctx, tx, id, []string{})
+	if err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM articles WHERE id = $1", sql.Named("p1", id))
+	if err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
+		return
+	}
+
+	// delete file lama jika ada
+	if oldImage != "" {
+		_, filename := filepath.Split(oldImage)
+		if err := utils.RemoveUpload(c, "uploads/"+filename); err != nil {
+			utils.Logger(c).Error("Failed to delete thumbnail", "error", err)
+		}
+	}
+	removeUnusedMedia(c, removedMedia)
+
+	cache.Invalidate("articles", "sitemap")
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
@@ -232,20 +485,18 @@ func DeleteArticle(c *gin.Context) {
 
 // hitung views artikel
 func IncrementArticleViews(c *gin.Context) {
+	ctx := c.Request.Context()
 	slugParam := c.Param("slug")
 
 	// Update views: tambahkan 1 ke kolom views
-	result, err := config.DB.Exec(`
+	result, err := config.DB.ExecContext(ctx, `
 		UPDATE articles
 		SET views = views + 1
 		WHERE slug = $1
 	`, sql.Named("p1", slugParam))
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "Failed to update views",
-			"detail": err.Error(),
-		})
+		utils.ServerError(c, "Failed to update views", err)
 		return
 	}
 
@@ -255,7 +506,187 @@ func IncrementArticleViews(c *gin.Context) {
 		return
 	}
 
+	// views ikut tampil di response artikel yang di-cache
+	cache.Invalidate("articles")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Views updated +1",
 	})
 }
+
+// query artikel beserta penulis dan co-author, where dan suffix (order / limit) opsional.
+// isi lengkap (description, content_html, toc) hanya dimuat jika full
+func fetchArticles(ctx context.Context, full bool, where, suffix string, args ...interface{}) ([]model.ResponseArticle, error) {
+	var article []model.ResponseArticle
+
+	// artikel lama (content_html kosong) tetap butuh description untuk menghitung excerpt
+	body := "CASE WHEN a.content_html = '' THEN a.description ELSE '' END, '', '[]'"
+	if full {
+		body = "a.description, a.content_html, a.toc"
+	}
+
+	query := `
+		SELECT a.id, a.title, a.slug, ` + body + `, a.content_format, a.excerpt, a.word_count, a.reading_time, a.thumbnail,
+			a.meta_title, a.meta_description, a.canonical_url, a.og_image, a.robots, a.views, a.created_at, a.updated_at,
+			u.id, u.name, COALESCE(ap.slug, ''), COALESCE(NULLIF(ap.avatar, ''), u.profile), c.category
+		FROM articles a
+		JOIN users u ON a.user_id = u.id
+		LEFT JOIN author_profiles ap ON ap.user_id = u.id
+		JOIN category_articles c ON a.category_id = c.id
+	`
+	if where != "" {
+		query += " WHERE " + where
+	}
+	query += " " + suffix
+
+	rows, err := config.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articleIds := []int{}
+	for rows.Next() {
+		var art model.ResponseArticle
+		var toc []byte
+		if err := rows.Scan(&art.Id, &art.Title, &art.Slug, &art.Description, &art.ContentHTML, &toc, &art.ContentFormat, &art.Excerpt, &art.WordCount, &art.ReadingTime, &art.Thumbnail,
+			&art.Seo.MetaTitle, &art.Seo.MetaDescription, &art.Seo.CanonicalUrl, &art.Seo.OgImage, &art.Seo.Robots, &art.Views, &art.CreatedAt, &art.UpdatedAt, &art.Author.Id, &art.Author.Name, &art.Author.Slug, &art.Author.Profile, &art.Category); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(toc, &art.Toc); err != nil {
+			return nil, err
+		}
+
+		// artikel lama belum punya hasil render
+		if art.ContentHTML == "" && art.Description != "" {
+			fillLegacyArticle(&art)
+		}
+		if !full {
+			art.Description, art.ContentHTML, art.Toc = "", "", nil
+		}
+		art.Seo = seo.Resolve(art.Seo, "article", art.Slug, art.Title, art.Excerpt, art.Thumbnail)
+
+		article = append(article, art)
+		articleIds = append(articleIds, art.Id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// ambil co-author untuk semua artikel sekaligus
+	coAuthors, err := getArticleCoAuthors(ctx, articleIds)
+	if err != nil {
+		return nil, err
+	}
+	for i := range article {
+		article[i].CoAuthors = coAuthors[article[i].Id]
+		if article[i].CoAuthors == nil {
+			article[i].CoAuthors = []model.ArticleAuthor{}
+		}
+	}
+
+	return article, nil
+}
+
+// ambil co-author untuk beberapa artikel, hasil di-group per article_id
+func getArticleCoAuthors(ctx context.Context, articleIds []int) (map[int][]model.ArticleAuthor, error) {
+	result := map[int][]model.ArticleAuthor{}
+	if len(articleIds) == 0 {
+		return result, nil
+	}
+
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT aa.article_id, u.id, u.name, COALESCE(ap.slug, ''), COALESCE(NULLIF(ap.avatar, ''), u.profile)
+		FROM article_authors aa
+		JOIN users u ON aa.user_id = u.id
+		LEFT JOIN author_profiles ap ON ap.user_id = u.id
+		WHERE aa.article_id = ANY($1)
+		ORDER BY aa.created_at, u.id
+	`, pq.Array(articleIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId int
+		var author model.ArticleAuthor
+		if err := rows.Scan(&articleId, &author.Id, &author.Name, &author.Slug, &author.Profile); err != nil {
+			return nil, err
+		}
+		result[articleId] = append(result[articleId], author)
+	}
+
+	return result, rows.Err()
+}
+
+// simpan co-author, penulis utama dan id duplikat diabaikan
+func saveArticleCoAuthors(ctx context.Context, tx *sql.Tx, articleId, authorId int, coAuthors []int) error {
+	for _, userId := range normalizeCoAuthors(authorId, coAuthors) {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO article_authors (article_id, user_id, created_at)
+			VALUES ($1, $2, $3)
+		`, articleId, userId, time.Now())
+		if err != nil {
+			return fmt.Errorf("co-author %d: %w", userId, err)
+		}
+	}
+	return nil
+}
+
+// user id penulis / co-author harus ada, dicek sebelum upload dan transaksi
+// agar error database lain tetap dilaporkan sebagai server error
+var errArticleAuthorNotFound = errors.New("author not found")
+
+func checkArticleAuthors(ctx context.Context, authorId int, coAuthors []int) error {
+	for _, userId := range append([]int{authorId}, normalizeCoAuthors(authorId, coAuthors)...) {
+		var exists bool
+		err := config.DB.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)", userId).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %d", errArticleAuthorNotFound, userId)
+		}
+	}
+	return nil
+}
+
+// balas 400 untuk user id yang tidak dikenal, selain itu server error
+func articleAuthorsError(c *gin.Context, err error) {
+	if errors.Is(err, errArticleAuthorNotFound) {
+		c.Error(err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid author or co-authors"})
+		return
+	}
+	utils.ServerError(c, "Database error", err)
+}
+
+func normalizeCoAuthors(authorId int, coAuthors []int) []int {
+	seen := map[int]bool{authorId: true}
+	result := []int{}
+	for _, userId := range coAuthors {
+		if userId <= 0 || seen[userId] {
+			continue
+		}
+		seen[userId] = true
+		result = append(result, userId)
+	}
+	return result
+}
+
+func sameCoAuthors(current []model.ArticleAuthor, ids []int) bool {
+	if len(current) != len(ids) {
+		return false
+	}
+	existing := map[int]bool{}
+	for _, author := range current {
+		existing[author.Id] = true
+	}
+	for _, id := range ids {
+		if !existing[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ekstensi gambar yang boleh dipakai di isi artikel
+var articleImageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true}
+
+// upload gambar untuk isi artikel, url yang dikembalikan dipakai di body artikel
+func UploadArticleImage(c *gin.Context) {
+	file, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image is required"})
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !articleImageExts[ext] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image must be jpg, jpeg, png, gif or webp"})
+		return
+	}
+
+	os.MkdirAll("uploads", os.ModePerm)
+	filename := uuid.New().String() + ext
+	if err := utils.SaveUpload(c, file, "uploads/"+filename); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
+		return
+	}
+
+	// dicatat agar hanya gambar ini yang bisa dipakai dan dihapus lewat artikel
+	_, err = config.DB.ExecContext(c.Request.Context(), `
+		INSERT INTO article_uploads (path, uploaded_by, created_at) VALUES ($1, $2, $3)
+	`, "/uploads/"+filename, c.GetInt("user_id"), time.Now())
+	if err != nil {
+		utils.RemoveUpload(c, "uploads/"+filename)
+		utils.ServerError(c, "Failed to save image", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "upload")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Image uploaded successfully",
+		"url":     "/uploads/" + filename,
+	})
+}
+
+// simpan relasi artikel dengan gambar di isinya, kembalikan gambar yang tidak lagi dipakai artikel ini
+func saveArticleMedia(ctx context.Context, tx *sql.Tx, articleId int, images []string) ([]string, error) {
+	var removed []string
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM article_media WHERE article_id = $1 AND NOT (path = ANY($2))
+		RETURNING path
+	`, articleId, pq.Array(images))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		removed = append(removed, path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, image := range images {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO article_media (article_id, path, created_at)
+			VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING
+		`, articleId, image, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return removed, nil
+}
+
+// hapus gambar artikel yang sudah tidak dipakai di isi maupun thumbnail artikel mana pun,
+// file yang tidak diupload lewat endpoint gambar artikel tidak pernah dihapus di sini
+func removeUnusedMedia(c *gin.Context, paths []string) {
+	for _, path := range paths {
+		var removed bool
+		err := config.DB.QueryRowContext(c.Request.Context(), `
+			WITH deleted AS (
+				DELETE FROM article_uploads
+				WHERE path = $1
+					AND NOT EXISTS(SELECT 1 FROM article_media WHERE path = $1)
+					AND NOT EXISTS(SELECT 1 FROM articles WHERE thumbnail = $1)
+				RETURNING path
+			)
+			SELECT EXISTS(SELECT 1 FROM deleted)
+		`, path).Scan(&removed)
+		if err != nil {
+			utils.Logger(c).Error("Failed to check article media", "error", err, "path", path)
+			continue
+		}
+		if removed {
+			utils.RemoveUpload(c, filepath.Join("uploads", filepath.Base(path)))
+		}
+	}
+}
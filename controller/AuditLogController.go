@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// get audit log dengan filter
+func GetAllAuditLog(c *gin.Context) {
+	ctx := c.Request.Context()
+	// audit log hanya untuk admin/superadmin
+	if !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to view audit logs"})
+		return
+	}
+
+	var filter model.AuditLogFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(filter)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 20
+	}
+
+	// susun kondisi where sesuai filter yang diisi
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(query string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(query, len(args)))
+	}
+
+	if filter.ActorId != 0 {
+		addCondition("actor_id = $%d", filter.ActorId)
+	}
+	if filter.Action != "" {
+		addCondition("action = $%d", filter.Action)
+	}
+	if filter.ResourceType != "" {
+		addCondition("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceId != 0 {
+		addCondition("resource_id = $%d", filter.ResourceId)
+	}
+	if filter.From != "" {
+		addCondition("created_at >= $%d::date", filter.From)
+	}
+	if filter.To != "" {
+		addCondition("created_at < $%d::date + INTERVAL '1 day'", filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err = config.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs"+where, args...).Scan(&total)
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	rows, err := config.DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, actor_id, action, resource_type, resource_id, before, after, ip, user_agent, created_at
+		FROM audit_logs%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+	defer rows.Close()
+
+	logs := []model.AuditLog{}
+	for rows.Next() {
+		var auditLog model.AuditLog
+		var before, after []byte
+		if err := rows.Scan(&auditLog.Id, &auditLog.ActorId, &auditLog.Action, &auditLog.ResourceType, &auditLog.ResourceId, &before, &after, &auditLog.Ip, &auditLog.UserAgent, &auditLog.CreatedAt); err != nil {
+			utils.ServerError(c, "Failed to fetch data", err)
+			return
+		}
+		auditLog.Before = before
+		auditLog.After = after
+		logs = append(logs, auditLog)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": logs,
+		"meta": gin.H{
+			"page":  filter.Page,
+			"limit": filter.Limit,
+			"total": total,
+		},
+	})
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetAllAuditLogForbiddenForNonAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, role := range []string{"user", "author", ""} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/admin/audit-logs", nil)
+		c.Set("user_id", 1)
+		c.Set("role", role)
+
+		GetAllAuditLog(c)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("role %q: status = %d, want %d", role, w.Code, http.StatusForbidden)
+		}
+	}
+}
@@ -1,57 +1,330 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/metrics"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret = []byte("secret-codetech") // sebaiknya ambil dari ENV
+const tokenPurposeTwoFactor = "2fa_challenge"
+
+// hash dummy agar waktu response sama saat email tidak terdaftar
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("codetech-dummy-password"), bcrypt.DefaultCost)
 
 func Login(c *gin.Context) {
+	ctx := c.Request.Context()
 	email := c.PostForm("email")
 	password := c.PostForm("password")
 
+	emailKey, ipKey := loginThrottleKeys(email, c.ClientIP())
+
+	// tolak sebelum bcrypt jika akun atau ip sedang terkunci
+	remaining, err := loginLockRemaining(ctx, emailKey, ipKey)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if remaining > 0 {
+		metrics.RecordLogin("locked")
+		tooManyLoginAttempts(c, remaining)
+		return
+	}
+
 	var user model.User
-	err := config.DB.QueryRow(
+	err = config.DB.QueryRowContext(ctx,
 		"SELECT id, email, password FROM users WHERE email = $1", email,
 	).Scan(&user.Id, &user.Email, &user.Password)
 
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		loginFailed(c, nil, email, emailKey, ipKey)
 		return
 	}
 
 	// Compare password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		loginFailed(c, &user.Id, email, emailKey, ipKey)
 		return
 	}
 
-	// Generate JWT token (expired in 1 hour)
-	expirationTime := time.Now().Add(1 * time.Hour)
+	// counter ip tidak direset, login sukses dari akun sendiri tidak boleh membuka kunci brute force ke akun lain
+	if err := clearLoginThrottle(ctx, emailKey); err != nil {
+		utils.Logger(c).Error("Failed to clear login throttle", "error", err)
+	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": user.Id,
-		"exp":     expirationTime.Unix(),
-		"iat":     time.Now().Unix(),
+	// jika 2FA aktif, kirim challenge token dulu sebelum token asli
+	var twoFactorEnabled bool
+	err = config.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM user_two_factors WHERE user_id = $1 AND enabled_at IS NOT NULL)", user.Id).Scan(&twoFactorEnabled)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	if twoFactorEnabled {
+		challengeToken, err := generateChallengeToken(user.Id)
+		if err != nil {
+			utils.ServerError(c, "Failed to generate token", err)
+			return
+		}
+
+		metrics.RecordLogin("challenge")
+		c.JSON(http.StatusOK, gin.H{
+			"message":             "Two-factor authentication required",
+			"two_factor_required": true,
+			"challenge_token":     challengeToken,
+		})
+		return
+	}
+
+	tokenString, err := generateAccessToken(c, user.Id)
+	if err != nil {
+		utils.ServerError(c, "Failed to generate token", err)
+		return
+	}
+
+	metrics.RecordLogin("success")
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Login successfully",
+		"token":   tokenString,
 	})
 
-	tokenString, err := token.SignedString(jwtSecret)
+}
+
+// langkah kedua login: tukar challenge token + kode 2FA dengan token asli
+func LoginTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.TwoFactorLoginRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	token, err := utils.ParseToken(req.ChallengeToken)
+	if err != nil || !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	userIdClaim, okId := claims["user_id"].(float64)
+	if !ok || !okId || claims["purpose"] != tokenPurposeTwoFactor {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired challenge token"})
+		return
+	}
+	userId := int(userIdClaim)
+
+	// percobaan kode 2FA ikut dibatasi seperti password
+	twoFactorKey := fmt.Sprintf("2fa:%d", userId)
+	_, ipKey := loginThrottleKeys("", c.ClientIP())
+	remaining, err := loginLockRemaining(ctx, twoFactorKey, ipKey)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if remaining > 0 {
+		metrics.RecordLogin("locked")
+		tooManyLoginAttempts(c, remaining)
 		return
 	}
 
+	valid, err := verifyTwoFactorCode(ctx, userId, req.Code)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if !valid {
+		metrics.RecordLogin("failure")
+		_, lockedUntil, err := registerLoginFailure(ctx, twoFactorKey, config.GetEnvInt("LOGIN_MAX_ATTEMPTS", 5))
+		if err != nil {
+			utils.Logger(c).Error("Failed to register login failure", "error", err)
+		}
+		if lockedUntil != nil {
+			utils.RecordAudit(c, "lockout", "users", &userId, nil, nil)
+			tooManyLoginAttempts(c, time.Until(*lockedUntil))
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+
+	if err := clearLoginThrottle(ctx, twoFactorKey); err != nil {
+		utils.Logger(c).Error("Failed to clear login throttle", "error", err)
+	}
+
+	tokenString, err := generateAccessToken(c, userId)
+	if err != nil {
+		utils.ServerError(c, "Failed to generate token", err)
+		return
+	}
+
+	metrics.RecordLogin("success")
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Login successfully",
 		"token":   tokenString,
 	})
+}
+
+// Generate JWT token (expired in 1 hour), setiap token punya sesi sendiri
+func generateAccessToken(c *gin.Context, userId int) (string, error) {
+	ctx := c.Request.Context()
+	ttl := 1 * time.Hour
+	expirationTime := time.Now().Add(ttl)
+
+	// expires_at dihitung dengan NOW() di database agar sama dengan pembanding di AuthMiddleware
+	// (kolom TIMESTAMP tanpa zona waktu, jam proses Go bisa beda zona dengan database)
+	sessionId := uuid.New().String()
+	_, err := config.DB.ExecContext(ctx, `
+		INSERT INTO user_sessions (id, user_id, ip, user_agent, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, NOW() + $5 * INTERVAL '1 second', $6)
+	`, sessionId, userId, c.ClientIP(), c.Request.UserAgent(), int(ttl.Seconds()), time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	return utils.SignToken(jwt.MapClaims{
+		"user_id": userId,
+		"sid":     sessionId,
+		"exp":     expirationTime.Unix(),
+		"iat":     time.Now().Unix(),
+	})
+}
+
+// cabut semua sesi user kecuali sesi yang dikecualikan (boleh kosong)
+func revokeUserSessions(ctx context.Context, userId int, exceptSessionId string) error {
+	_, err := config.DB.ExecContext(ctx, `
+		UPDATE user_sessions
+		SET revoked_at = $1
+		WHERE user_id = $2 AND id != $3 AND revoked_at IS NULL
+	`, time.Now(), userId, exceptSessionId)
+	return err
+}
+
+// challenge token hanya berlaku 5 menit dan ditolak oleh AuthMiddleware
+func generateChallengeToken(userId int) (string, error) {
+	return utils.SignToken(jwt.MapClaims{
+		"user_id": userId,
+		"purpose": tokenPurposeTwoFactor,
+		"exp":     time.Now().Add(5 * time.Minute).Unix(),
+		"iat":     time.Now().Unix(),
+	})
+}
+
+// catat gagal login per akun dan per ip, lockout dicatat di audit log
+func loginFailed(c *gin.Context, userId *int, email, emailKey, ipKey string) {
+	metrics.RecordLogin("failure")
+
+	// tetap dicatat walau client memutus koneksi di tengah login
+	ctx := context.WithoutCancel(c.Request.Context())
+	limits := map[string]int{
+		emailKey: config.GetEnvInt("LOGIN_MAX_ATTEMPTS", 5),
+		ipKey:    config.GetEnvInt("LOGIN_IP_MAX_ATTEMPTS", 20),
+	}
+
+	var lockedFor time.Duration
+	for key, maxAttempts := range limits {
+		failures, lockedUntil, err := registerLoginFailure(ctx, key, maxAttempts)
+		if err != nil {
+			utils.Logger(c).Error("Failed to register login failure", "error", err)
+			continue
+		}
+		if lockedUntil == nil {
+			continue
+		}
+
+		if left := time.Until(*lockedUntil); left > lockedFor {
+			lockedFor = left
+		}
+
+		detail, _ := json.Marshal(gin.H{
+			"key":          key,
+			"email":        email,
+			"ip":           c.ClientIP(),
+			"failures":     failures,
+			"locked_until": lockedUntil,
+		})
+		utils.RecordAudit(c, "lockout", "users", userId, nil, detail)
+	}
+
+	if lockedFor > 0 {
+		tooManyLoginAttempts(c, lockedFor)
+		return
+	}
+
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+}
+
+func tooManyLoginAttempts(c *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error":       "Too many login attempts, please try again later",
+		"retry_after": seconds,
+	})
+}
+
+// buka kunci akun yang terkena lockout (khusus role privileged)
+func UnlockUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	if !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to unlock users"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
 
+	var email string
+	err = config.DB.QueryRowContext(ctx, "SELECT email FROM users WHERE id = $1", id).Scan(&email)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	emailKey, _ := loginThrottleKeys(email, "")
+	if err := clearLoginThrottle(ctx, emailKey); err != nil {
+		utils.ServerError(c, "Failed to unlock user", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "unlock")
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+}
+
+// public key untuk verifikasi token admin oleh service lain
+func GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{
+		"keys": utils.JWKS(),
+	})
 }
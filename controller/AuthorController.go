@@ -0,0 +1,232 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/cache"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+)
+
+// get profil penulis berdasarkan slug
+func GetAuthorBySlug(c *gin.Context) {
+	ctx := c.Request.Context()
+	var author model.AuthorProfile
+
+	err := config.DB.QueryRowContext(ctx, `
+		SELECT u.id, u.name, ap.slug, ap.bio, COALESCE(NULLIF(ap.avatar, ''), u.profile),
+			ap.website, ap.twitter, ap.github, ap.linkedin, ap.instagram, ap.created_at
+		FROM author_profiles ap
+		JOIN users u ON ap.user_id = u.id
+		WHERE ap.slug = $1
+	`, c.Param("slug")).Scan(
+		&author.Id, &author.Name, &author.Slug, &author.Bio, &author.Avatar,
+		&author.SocialLinks.Website, &author.SocialLinks.Twitter, &author.SocialLinks.Github,
+		&author.SocialLinks.Linkedin, &author.SocialLinks.Instagram, &author.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": author,
+	})
+}
+
+// get artikel milik penulis (termasuk sebagai co-author) dengan pagination
+func GetAuthorArticles(c *gin.Context) {
+	ctx := c.Request.Context()
+	var query model.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(query)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+
+	var authorId int
+	err = config.DB.QueryRowContext(ctx, "SELECT user_id FROM author_profiles WHERE slug = $1", c.Param("slug")).Scan(&authorId)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+
+	where := "(a.user_id = $1 OR EXISTS (SELECT 1 FROM article_authors aa WHERE aa.article_id = a.id AND aa.user_id = $1))"
+
+	var total int
+	err = config.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM articles a WHERE "+where, authorId).Scan(&total)
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+
+	articles, err := fetchArticles(ctx, false, where, "ORDER BY a.created_at DESC, a.id DESC LIMIT $2 OFFSET $3", authorId, query.Limit, (query.Page-1)*query.Limit)
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+	if articles == nil {
+		articles = []model.ResponseArticle{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": articles,
+		"meta": gin.H{
+			"page":  query.Page,
+			"limit": query.Limit,
+			"total": total,
+		},
+	})
+}
+
+// update profil penulis, hanya pemilik profil atau role privileged
+func UpdateAuthorProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	if id != c.GetInt("user_id") && !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this profile"})
+		return
+	}
+
+	var req model.AuthorProfileRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err = config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Ambil data profil lama
+	var oldSlug, oldAvatar string
+	err = config.DB.QueryRowContext(ctx, "SELECT slug, avatar FROM author_profiles WHERE user_id = $1", id).Scan(&oldSlug, &oldAvatar)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	profileSlug := oldSlug
+	if req.Slug != "" {
+		profileSlug = slug.Make(req.Slug)
+	}
+
+	// Cek slug duplicate (kecuali milik user ini)
+	var slugExists bool
+	err = config.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM author_profiles WHERE slug = $1 AND user_id != $2)", profileSlug, id).Scan(&slugExists)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if slugExists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Slug already exists"})
+		return
+	}
+
+	avatar := oldAvatar
+	file, err := c.FormFile("avatar")
+	if err == nil {
+		os.MkdirAll("uploads", os.ModePerm)
+		filename := uuid.New().String() + filepath.Ext(file.Filename)
+		savePath := filepath.Join("uploads", filename)
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
+			return
+		}
+
+		// Hapus file lama jika ada
+		if oldAvatar != "" {
+			_, oldFile := filepath.Split(oldAvatar)
+			utils.RemoveUpload(c, filepath.Join("uploads", oldFile))
+		}
+
+		avatar = "/uploads/" + filename
+	}
+
+	_, err = config.DB.ExecContext(ctx, `
+		UPDATE author_profiles
+		SET slug = $1, bio = $2, avatar = $3, website = $4, twitter = $5, github = $6, linkedin = $7, instagram = $8, updated_at = $9
+		WHERE user_id = $10
+	`, profileSlug, req.Bio, avatar, req.Website, req.Twitter, req.Github, req.Linkedin, req.Instagram, time.Now(), id)
+
+	if err != nil {
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	cache.Invalidate("articles")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// buat profil penulis default saat user baru dibuat
+func createAuthorProfile(ctx context.Context, userId int, name string) error {
+	profileSlug := slug.Make(name)
+
+	var exists bool
+	err := config.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM author_profiles WHERE slug = $1)", profileSlug).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists || profileSlug == "" {
+		profileSlug = fmt.Sprintf("%s-%d", profileSlug, userId)
+	}
+
+	_, err = config.DB.ExecContext(ctx, `
+		INSERT INTO author_profiles (user_id, slug, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+	`, userId, profileSlug, time.Now(), time.Now())
+	return err
+}
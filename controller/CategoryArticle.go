@@ -6,19 +6,22 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 // get all category
 func GetAllCategoryArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	var categoryArticles []model.CategoryArticle
 
-	rows, err := config.DB.Query("SELECT id, category, created_at, updated_at FROM category_articles")
+	rows, err := config.DB.QueryContext(ctx, "SELECT id, category, created_at, updated_at FROM category_articles")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -29,7 +32,7 @@ func GetAllCategoryArticle(c *gin.Context) {
 	for rows.Next() {
 		var categoryArticle model.CategoryArticle
 		if err := rows.Scan(&categoryArticle.Id, &categoryArticle.Category, &categoryArticle.CreatedAt, &categoryArticle.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
 		categoryArticles = append(categoryArticles, categoryArticle)
@@ -42,6 +45,7 @@ func GetAllCategoryArticle(c *gin.Context) {
 
 // create category article
 func CreateCategoryArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	category := c.PostForm("category")
 
 	var req model.CategoryArticleRequest
@@ -61,15 +65,22 @@ func CreateCategoryArticle(c *gin.Context) {
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
 		INSERT INTO category_articles (category, created_at, updated_at)
 		VALUES ($1, $2, $3)
-	`, category, time.Now(), time.Now())
+		RETURNING id
+	`, category, time.Now(), time.Now()).Scan(&newId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	cache.Invalidate("articles", "sitemap")
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -77,6 +88,7 @@ func CreateCategoryArticle(c *gin.Context) {
 
 // update category article
 func UpdateCategoryArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	id := c.Param("id")
 	category := c.PostForm("category")
 
@@ -99,23 +111,25 @@ func UpdateCategoryArticle(c *gin.Context) {
 
 	// check database
 	var categoryArticle model.CategoryArticle
-	err = config.DB.QueryRow("SELECT id FROM category_articles WHERE id = $1", sql.Named("p1", id)).Scan(&categoryArticle.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM category_articles WHERE id = $1", sql.Named("p1", id)).Scan(&categoryArticle.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		UPDATE category_articles
 		SET category = $1, updated_at = $2
 		WHERE id = $3
 	`, category, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
+	cache.Invalidate("articles", "sitemap")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data updated successfully",
 	})
@@ -123,26 +137,29 @@ func UpdateCategoryArticle(c *gin.Context) {
 
 // delete category article
 func DeleteCategoryArticle(c *gin.Context) {
+	ctx := c.Request.Context()
 	id := c.Param("id")
 
 	// check database
 	var categoryArticle model.CategoryArticle
-	err := config.DB.QueryRow("SELECT id FROM category_articles WHERE id = $1", sql.Named("p1", id)).Scan(&categoryArticle.Id)
+	err := config.DB.QueryRowContext(ctx, "SELECT id FROM category_articles WHERE id = $1", sql.Named("p1", id)).Scan(&categoryArticle.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		DELETE FROM category_articles
 		WHERE id = $1
 	`, id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
+	cache.Invalidate("articles", "sitemap")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -17,11 +19,12 @@ import (
 
 // get all data
 func GetAllCategoryFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	var categoryFaqs []model.CategoryFaq
 
-	rows, err := config.DB.Query("SELECT id, category, description, icon, created_at, updated_at FROM category_faqs")
+	rows, err := config.DB.QueryContext(ctx, "SELECT id, category, description, icon, created_at, updated_at FROM category_faqs")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -32,7 +35,7 @@ func GetAllCategoryFaq(c *gin.Context) {
 	for rows.Next() {
 		var categoryFaq model.CategoryFaq
 		if err := rows.Scan(&categoryFaq.Id, &categoryFaq.Category, &categoryFaq.Description, &categoryFaq.Icon, &categoryFaq.CreatedAt, &categoryFaq.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
 		categoryFaqs = append(categoryFaqs, categoryFaq)
@@ -45,6 +48,7 @@ func GetAllCategoryFaq(c *gin.Context) {
 
 // create data
 func CreateCategoryFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	category := c.PostForm("category")
 	description := c.PostForm("description")
 
@@ -74,23 +78,30 @@ func CreateCategoryFaq(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
 	icon := "/uploads/" + filename
 
-	_, err = config.DB.Exec(`
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
 		INSERT INTO category_faqs (category, description, icon, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5)
-	`, category, description, icon, time.Now(), time.Now())
+		RETURNING id
+	`, category, description, icon, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	cache.Invalidate("faqs")
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -98,6 +109,7 @@ func CreateCategoryFaq(c *gin.Context) {
 
 // update data
 func UpdateCategoryFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	id := c.Param("id")
 	category := c.PostForm("category")
 	description := c.PostForm("description")
@@ -121,12 +133,12 @@ func UpdateCategoryFaq(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan icon lama
 	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM category_faqs WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
+	err = config.DB.QueryRowContext(ctx, "SELECT icon FROM category_faqs WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing category", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing category", err)
 		return
 	}
 
@@ -138,8 +150,8 @@ func UpdateCategoryFaq(c *gin.Context) {
 		os.MkdirAll("uploads", os.ModePerm)
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := "uploads/" + filename
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 
@@ -148,24 +160,26 @@ func UpdateCategoryFaq(c *gin.Context) {
 			_, imageFile := filepath.Split(oldIcon)
 			imagePath := filepath.Join("uploads", imageFile)
 			if _, err := os.Stat(imagePath); err == nil {
-				_ = os.Remove(imagePath) // jika gagal dihapus, bisa di-log tapi tidak perlu menghentikan proses
+				_ = utils.RemoveUpload(c, imagePath) // jika gagal dihapus, bisa di-log tapi tidak perlu menghentikan proses
 			}
 		}
 
 		icon = "/uploads/" + filename // set icon baru
 	}
 
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		UPDATE category_faqs
 		SET category = $1, description = $2, icon = $3, updated_at = $4
 		WHERE id = $5
 	`, category, description, icon, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
+	cache.Invalidate("faqs")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data updated successfully",
 	})
@@ -173,16 +187,27 @@ func UpdateCategoryFaq(c *gin.Context) {
 
 // delete data
 func DeleteCategoryFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	id := c.Param("id")
 
 	// Ambil data lama untuk dapatkan icon lama
 	var oldIcon string
-	err := config.DB.QueryRow("SELECT icon FROM category_faqs WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
+	err := config.DB.QueryRowContext(ctx, "SELECT icon FROM category_faqs WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing category", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing category", err)
+		return
+	}
+
+	_, err = config.DB.ExecContext(ctx, `
+		DELETE FROM category_faqs
+		WHERE id = $1
+	`, id)
+
+	if err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
@@ -191,22 +216,13 @@ func DeleteCategoryFaq(c *gin.Context) {
 		_, imageFile := filepath.Split(oldIcon)
 		imagePath := filepath.Join("uploads", imageFile)
 		if _, err := os.Stat(imagePath); err == nil {
-			if err := os.Remove(imagePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image", "detail": err.Error()})
-				return
+			if err := utils.RemoveUpload(c, imagePath); err != nil {
+				utils.Logger(c).Error("Failed to delete image", "error", err, "path", imagePath)
 			}
 		}
 	}
 
-	_, err = config.DB.Exec(`
-		DELETE FROM category_faqs
-		WHERE id = $1
-	`, id)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
-		return
-	}
+	cache.Invalidate("faqs")
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
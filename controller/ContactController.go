@@ -9,15 +9,17 @@ import (
 
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 // get all data
 func GetAllContact(c *gin.Context) {
+	ctx := c.Request.Context()
 	var contact model.Contact
 
-	err := config.DB.QueryRow("SELECT id, phone, email, address, office_operation, created_at, updated_at FROM contacts").Scan(
+	err := config.DB.QueryRowContext(ctx, "SELECT id, phone, email, address, office_operation, created_at, updated_at FROM contacts").Scan(
 		&contact.Id, &contact.Phone, &contact.Email, &contact.Address, &contact.OfficeOperation, &contact.CreatedAt, &contact.UpdatedAt,
 	)
 
@@ -26,7 +28,7 @@ func GetAllContact(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "No data found"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -38,6 +40,7 @@ func GetAllContact(c *gin.Context) {
 
 // create data
 func CreateContact(c *gin.Context) {
+	ctx := c.Request.Context()
 	phone := c.PostForm("phone")
 	email := c.PostForm("email")
 	address := c.PostForm("address")
@@ -62,20 +65,24 @@ func CreateContact(c *gin.Context) {
 
 	// check apakah data sudah ada atau tidak
 	var contact model.Contact
-	err = config.DB.QueryRow("SELECT id FROM contacts WHERE phone = $1", sql.Named("p1", phone)).Scan(&contact.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM contacts WHERE phone = $1", sql.Named("p1", phone)).Scan(&contact.Id)
 	if err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Data already exists"})
 		return
 	}
 
-	_, err = config.DB.Exec(`INSERT INTO contacts (phone, email, address, office_operation, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`,
-		phone, email, address, officeOperation, time.Now(), time.Now())
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `INSERT INTO contacts (phone, email, address, office_operation, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		phone, email, address, officeOperation, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -83,6 +90,7 @@ func CreateContact(c *gin.Context) {
 
 // update data
 func UpdateContact(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -114,17 +122,17 @@ func UpdateContact(c *gin.Context) {
 
 	// check apakah data ada dengan id tersebut
 	var contact model.Contact
-	err = config.DB.QueryRow("SELECT id FROM contacts WHERE id = $1", sql.Named("p1", id)).Scan(&contact.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM contacts WHERE id = $1", sql.Named("p1", id)).Scan(&contact.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	}
 
-	_, err = config.DB.Exec(`UPDATE contacts SET phone = $1, email = $2, address = $3, office_operation = $4, updated_at = $5 WHERE id = $6`,
+	_, err = config.DB.ExecContext(ctx, `UPDATE contacts SET phone = $1, email = $2, address = $3, office_operation = $4, updated_at = $5 WHERE id = $6`,
 		phone, email, address, officeOperation, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
@@ -135,6 +143,7 @@ func UpdateContact(c *gin.Context) {
 
 // delete data
 func DeleteContact(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -144,16 +153,16 @@ func DeleteContact(c *gin.Context) {
 
 	// check apakah data ada dengan id tersebut
 	var contact model.Contact
-	err = config.DB.QueryRow("SELECT id FROM contacts WHERE id = $1", sql.Named("p1", id)).Scan(&contact.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM contacts WHERE id = $1", sql.Named("p1", id)).Scan(&contact.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	}
 
-	_, err = config.DB.Exec(`DELETE FROM contacts WHERE id = $1`, id)
+	_, err = config.DB.ExecContext(ctx, `DELETE FROM contacts WHERE id = $1`, id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
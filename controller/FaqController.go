@@ -7,17 +7,20 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 // get all data
 func GetAllFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	var faqs []model.FaqResponse
 
-	rows, err := config.DB.Query(`
+	rows, err := config.DB.QueryContext(ctx, `
 		SELECT
 			f.id,
 			f.question,
@@ -29,7 +32,7 @@ func GetAllFaq(c *gin.Context) {
 		JOIN category_faqs c ON f.category_id = c.id
 	`)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -40,7 +43,7 @@ func GetAllFaq(c *gin.Context) {
 	for rows.Next() {
 		var faq model.FaqResponse
 		if err := rows.Scan(&faq.Id, &faq.Question, &faq.Answer, &faq.Category, &faq.CreatedAt, &faq.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
 		faqs = append(faqs, faq)
@@ -53,6 +56,7 @@ func GetAllFaq(c *gin.Context) {
 
 // create data
 func CreateFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	question := c.PostForm("question")
 	answer := c.PostForm("answer")
 	categoryId := c.PostForm("category_id")
@@ -74,14 +78,20 @@ func CreateFaq(c *gin.Context) {
 		return
 	}
 
-	_, err = config.DB.Exec(`INSERT INTO faqs (question, answer, category_id, created_at, updated_at)
-	VALUES ($1, $2, $3, $4, $5)`,
-		question, answer, categoryId, time.Now(), time.Now())
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `INSERT INTO faqs (question, answer, category_id, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		question, answer, categoryId, time.Now(), time.Now()).Scan(&newId)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	cache.Invalidate("faqs")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data created successfully",
 	})
@@ -89,6 +99,7 @@ func CreateFaq(c *gin.Context) {
 
 // update data
 func UpdateFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -115,7 +126,7 @@ func UpdateFaq(c *gin.Context) {
 
 	// check database
 	var faq model.Faq
-	err = config.DB.QueryRow("SELECT id FROM faqs WHERE id = $1", sql.Named("p1", id)).Scan(&faq.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM faqs WHERE id = $1", sql.Named("p1", id)).Scan(&faq.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
@@ -125,14 +136,16 @@ func UpdateFaq(c *gin.Context) {
 	answer := c.PostForm("answer")
 	categoryId := c.PostForm("category_id")
 
-	_, err = config.DB.Exec(`UPDATE faqs SET question = $1, answer = $2, category_id = $3, updated_at = $4 WHERE id = $5`,
+	_, err = config.DB.ExecContext(ctx, `UPDATE faqs SET question = $1, answer = $2, category_id = $3, updated_at = $4 WHERE id = $5`,
 		question, answer, categoryId, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
+	cache.Invalidate("faqs")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data updated successfully",
 	})
@@ -140,6 +153,7 @@ func UpdateFaq(c *gin.Context) {
 
 // delete data
 func DeleteFaq(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -149,19 +163,21 @@ func DeleteFaq(c *gin.Context) {
 
 	// check database
 	var faq model.Faq
-	err = config.DB.QueryRow("SELECT id FROM faqs WHERE id = $1", sql.Named("p1", id)).Scan(&faq.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM faqs WHERE id = $1", sql.Named("p1", id)).Scan(&faq.Id)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
 	}
 
-	_, err = config.DB.Exec(`DELETE FROM faqs WHERE id = $1`, id)
+	_, err = config.DB.ExecContext(ctx, `DELETE FROM faqs WHERE id = $1`, id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
+	cache.Invalidate("faqs")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
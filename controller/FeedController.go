@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/feed"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/seo"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gosimple/slug"
+)
+
+// feed RSS 2.0 artikel terbaru
+func RSSFeed(c *gin.Context) {
+	serveFeed(c, "rss")
+}
+
+// feed Atom 1.0 artikel terbaru
+func AtomFeed(c *gin.Context) {
+	serveFeed(c, "atom")
+}
+
+// artikel terbaru (FEED_LIMIT, default 20), difilter per kategori jika ada param slug
+func serveFeed(c *gin.Context, format string) {
+	ctx := c.Request.Context()
+	baseURL := config.GetEnv("FEED_BASE_URL", seo.SitemapBaseURL())
+	channel := feed.Channel{
+		Title:       config.GetEnv("FEED_TITLE", "CodeTech"),
+		Description: config.GetEnv("FEED_DESCRIPTION", "Artikel terbaru dari CodeTech"),
+		Language:    config.GetEnv("FEED_LANGUAGE", "id"),
+		Link:        seo.SiteURL() + "/",
+		Self:        baseURL + c.Request.URL.Path,
+	}
+
+	where := ""
+	args := []interface{}{}
+	if categorySlug := c.Param("slug"); categorySlug != "" {
+		categoryId, category, err := categoryBySlug(ctx, categorySlug)
+		if err != nil {
+			utils.ServerError(c, "Failed to fetch data", err)
+			return
+		}
+		if categoryId == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+
+		where = "a.category_id = $1"
+		args = append(args, categoryId)
+		channel.Title += " - " + category
+		channel.Link = seo.URL("category", categorySlug)
+	}
+
+	limit := fmt.Sprintf("ORDER BY a.created_at DESC, a.id DESC LIMIT $%d", len(args)+1)
+	args = append(args, config.GetEnvInt("FEED_LIMIT", 20))
+	articles, err := fetchArticles(ctx, true, where, limit, args...)
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+
+	if format == "atom" {
+		c.Data(http.StatusOK, feed.AtomContentType, feed.Atom(channel, articles))
+		return
+	}
+	c.Data(http.StatusOK, feed.RSSContentType, feed.RSS(channel, articles))
+}
+
+// kategori belum punya kolom slug, dicocokkan dengan slug dari nama kategori
+func categoryBySlug(ctx context.Context, categorySlug string) (int, string, error) {
+	rows, err := config.DB.QueryContext(ctx, "SELECT id, category FROM category_articles ORDER BY id")
+	if err != nil {
+		return 0, "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category model.CategoryArticle
+		if err := rows.Scan(&category.Id, &category.Category); err != nil {
+			return 0, "", err
+		}
+		if slug.Make(category.Category) == categorySlug {
+			return category.Id, category.Category, nil
+		}
+	}
+	return 0, "", rows.Err()
+}
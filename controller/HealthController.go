@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gin-gonic/gin"
+)
+
+// liveness probe, proses masih hidup
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readiness probe, database dan folder upload siap dipakai
+func Readyz(c *gin.Context) {
+	checks := gin.H{}
+	ready := true
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	if config.DB == nil || config.DB.PingContext(ctx) != nil {
+		checks["database"] = "unavailable"
+		ready = false
+	} else {
+		checks["database"] = "ok"
+	}
+
+	if err := checkUploadDir("uploads"); err != nil {
+		checks["uploads"] = "not writable"
+		ready = false
+	} else {
+		checks["uploads"] = "ok"
+	}
+
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": checks})
+}
+
+// coba tulis file sementara di folder upload
+func checkUploadDir(dir string) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, ".readyz-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
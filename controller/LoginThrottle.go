@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+const (
+	throttleKeyEmail = "email:"
+	throttleKeyIp    = "ip:"
+)
+
+// key throttle untuk akun dan ip
+func loginThrottleKeys(email, ip string) (string, string) {
+	return throttleKeyEmail + strings.ToLower(strings.TrimSpace(email)), throttleKeyIp + ip
+}
+
+// sisa waktu lockout terlama dari key yang diberikan, 0 jika tidak terkunci
+func loginLockRemaining(ctx context.Context, keys ...string) (time.Duration, error) {
+	var remaining time.Duration
+	for _, key := range keys {
+		var lockedUntil sql.NullTime
+		err := config.DB.QueryRowContext(ctx, "SELECT locked_until FROM login_throttles WHERE throttle_key = $1", key).Scan(&lockedUntil)
+		if err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		if lockedUntil.Valid {
+			if left := time.Until(lockedUntil.Time); left > remaining {
+				remaining = left
+			}
+		}
+	}
+	return remaining, nil
+}
+
+// catat gagal login, kembalikan waktu lockout jika batas percobaan terlewati
+func registerLoginFailure(ctx context.Context, key string, maxAttempts int) (int, *time.Time, error) {
+	now := time.Now()
+	window := config.GetEnvDuration("LOGIN_ATTEMPT_WINDOW", time.Hour)
+
+	// hitungan direset jika gagal terakhir sudah lewat dari window
+	var failures int
+	err := config.DB.QueryRowContext(ctx, `
+		INSERT INTO login_throttles (throttle_key, failures, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (throttle_key) DO UPDATE
+		SET failures = CASE WHEN login_throttles.last_failed_at < $3 THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failed_at = $2
+		RETURNING failures
+	`, key, now, now.Add(-window)).Scan(&failures)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if failures < maxAttempts {
+		return failures, nil, nil
+	}
+
+	// exponential backoff: base, 2x base, 4x base, ... sampai batas maksimal
+	base := config.GetEnvDuration("LOGIN_LOCKOUT_BASE", time.Minute)
+	maxLock := config.GetEnvDuration("LOGIN_LOCKOUT_MAX", time.Hour)
+	lock := time.Duration(float64(base) * math.Pow(2, float64(failures-maxAttempts)))
+	if lock > maxLock || lock <= 0 {
+		lock = maxLock
+	}
+
+	lockedUntil := now.Add(lock)
+	_, err = config.DB.ExecContext(ctx, "UPDATE login_throttles SET locked_until = $1 WHERE throttle_key = $2", lockedUntil, key)
+	if err != nil {
+		return failures, nil, err
+	}
+
+	return failures, &lockedUntil, nil
+}
+
+// hapus hitungan gagal login (setelah login berhasil atau di-unlock admin)
+func clearLoginThrottle(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if _, err := config.DB.ExecContext(ctx, "DELETE FROM login_throttles WHERE throttle_key = $1", key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
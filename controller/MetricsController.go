@@ -0,0 +1,28 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// endpoint prometheus, jika METRICS_TOKEN diisi wajib pakai Bearer token tersebut.
+// di release mode METRICS_TOKEN wajib diisi agar metrik tidak terbuka untuk publik
+func Metrics() gin.HandlerFunc {
+	handler := promhttp.Handler()
+	token := config.GetEnv("METRICS_TOKEN", "")
+	if token == "" && gin.Mode() == gin.ReleaseMode {
+		config.Fatal("METRICS_TOKEN wajib diisi di release mode")
+	}
+
+	return func(c *gin.Context) {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
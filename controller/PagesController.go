@@ -3,15 +3,17 @@ package controller
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/seo"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -19,11 +21,15 @@ import (
 )
 
 func GetAllPages(c *gin.Context) {
+	ctx := c.Request.Context()
 	var pages []model.Pages
 
-	rows, err := config.DB.Query("SELECT id, title, slug, type, description, banner, created_at, updated_at FROM pages")
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT id, title, slug, type, description, banner, meta_title, meta_description, canonical_url, og_image, robots, created_at, updated_at
+		FROM pages
+	`)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -33,10 +39,13 @@ func GetAllPages(c *gin.Context) {
 
 	for rows.Next() {
 		var page model.Pages
-		if err := rows.Scan(&page.Id, &page.Title, &page.Slug, &page.Type, &page.Description, &page.Banner, &page.CreatedAt, &page.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		if err := rows.Scan(&page.Id, &page.Title, &page.Slug, &page.Type, &page.Description, &page.Banner,
+			&page.Seo.MetaTitle, &page.Seo.MetaDescription, &page.Seo.CanonicalUrl, &page.Seo.OgImage, &page.Seo.Robots,
+			&page.CreatedAt, &page.UpdatedAt); err != nil {
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
+		page.Seo = seo.Resolve(page.Seo, "page", page.Slug, page.Title, page.Description, page.Banner)
 		pages = append(pages, page)
 	}
 
@@ -46,6 +55,7 @@ func GetAllPages(c *gin.Context) {
 }
 
 func CreatePage(c *gin.Context) {
+	ctx := c.Request.Context()
 	title := c.PostForm("title")
 	description := c.PostForm("description")
 	types := c.PostForm("type")
@@ -76,21 +86,31 @@ func CreatePage(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
-	_, err = config.DB.Exec(`
-		INSERT INTO pages (title, slug, type, description, banner, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, title, slug.Make(title), types, description, "/uploads/"+filename, time.Now(), time.Now())
+	meta := seoFromForm(c, model.Seo{})
+
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
+		INSERT INTO pages (title, slug, type, description, banner, meta_title, meta_description, canonical_url, og_image, robots, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`, title, slug.Make(title), types, description, "/uploads/"+filename,
+		meta.MetaTitle, meta.MetaDescription, meta.CanonicalUrl, meta.OgImage, meta.Robots, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	cache.Invalidate("sitemap")
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Page created successfully",
 	})
@@ -98,6 +118,7 @@ func CreatePage(c *gin.Context) {
 
 // update
 func UpdatePage(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -128,12 +149,16 @@ func UpdatePage(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan banner lama
 	var oldBanner string
-	err = config.DB.QueryRow("SELECT banner FROM pages WHERE id = $1", sql.Named("p1", id)).Scan(&oldBanner)
+	var meta model.Seo
+	err = config.DB.QueryRowContext(ctx,
+		"SELECT banner, meta_title, meta_description, canonical_url, og_image, robots FROM pages WHERE id = $1",
+		sql.Named("p1", id),
+	).Scan(&oldBanner, &meta.MetaTitle, &meta.MetaDescription, &meta.CanonicalUrl, &meta.OgImage, &meta.Robots)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Page not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing page", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing page", err)
 		return
 	}
 
@@ -146,8 +171,8 @@ func UpdatePage(c *gin.Context) {
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := filepath.Join("uploads", filename)
 
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload new banner"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload new banner", err)
 			return
 		}
 
@@ -156,20 +181,23 @@ func UpdatePage(c *gin.Context) {
 			_, oldFile := filepath.Split(oldBanner)
 			oldFilePath := filepath.Join("uploads", oldFile)
 			if _, err := os.Stat(oldFilePath); err == nil {
-				os.Remove(oldFilePath)
+				utils.RemoveUpload(c, oldFilePath)
 			}
 		}
 
 		bannerPath = "/uploads/" + filename
 	}
 
+	meta = seoFromForm(c, meta)
+
 	// Update data
 	query := `
         UPDATE pages 
-        SET title = $1, slug = $2, type = $3, description = $4, banner = $5, updated_at = $6 
-        WHERE id = $7
+        SET title = $1, slug = $2, type = $3, description = $4, banner = $5, updated_at = $6,
+            meta_title = $7, meta_description = $8, canonical_url = $9, og_image = $10, robots = $11
+        WHERE id = $12
     `
-	_, err = config.DB.Exec(
+	_, err = config.DB.ExecContext(ctx,
 		query,
 		sql.Named("p1", title),
 		sql.Named("p2", slug.Make(title)),
@@ -177,14 +205,21 @@ func UpdatePage(c *gin.Context) {
 		sql.Named("p4", description),
 		sql.Named("p5", bannerPath),
 		sql.Named("p6", time.Now()),
-		sql.Named("p7", id),
+		sql.Named("p7", meta.MetaTitle),
+		sql.Named("p8", meta.MetaDescription),
+		sql.Named("p9", meta.CanonicalUrl),
+		sql.Named("p10", meta.OgImage),
+		sql.Named("p11", meta.Robots),
+		sql.Named("p12", id),
 	)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update page", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update page", err)
 		return
 	}
 
+	cache.Invalidate("sitemap")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Page updated successfully",
 	})
@@ -192,6 +227,7 @@ func UpdatePage(c *gin.Context) {
 
 // delete
 func DeletePage(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -201,19 +237,19 @@ func DeletePage(c *gin.Context) {
 
 	var page model.Pages
 	query := "SELECT id, banner FROM pages WHERE id = $1"
-	err = config.DB.QueryRow(query, id).Scan(&page.Id, &page.Banner)
+	err = config.DB.QueryRowContext(ctx, query, id).Scan(&page.Id, &page.Banner)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Page not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error", "detail": err.Error()})
+		utils.ServerError(c, "Database error", err)
 		return
 	}
 
 	deleteQuery := "DELETE FROM pages WHERE id = $1"
-	result, err := config.DB.Exec(deleteQuery, id)
+	result, err := config.DB.ExecContext(ctx, deleteQuery, id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete page", "detail": err.Error()})
+		utils.ServerError(c, "Failed to delete page", err)
 		return
 	}
 
@@ -227,11 +263,13 @@ func DeletePage(c *gin.Context) {
 	_, fileName := filepath.Split(page.Banner)
 	filePath := filepath.Join("uploads", fileName)
 	if _, err := os.Stat(filePath); err == nil {
-		if err := os.Remove(filePath); err != nil {
-			log.Printf("Failed to delete banner file: %s", err)
+		if err := utils.RemoveUpload(c, filePath); err != nil {
+			utils.Logger(c).Error("Failed to delete banner file", "error", err)
 		}
 	}
 
+	cache.Invalidate("sitemap")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Page deleted successfully",
 	})
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/mailer"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// kirim link reset password, response selalu sama walaupun email tidak terdaftar
+func ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// proses di background agar waktu response tidak membocorkan email terdaftar atau tidak
+	logger := utils.Logger(c)
+	email := req.Email
+	if !mailer.Jobs.Enqueue(func(ctx context.Context) { sendPasswordReset(ctx, logger, email) }) {
+		logger.Warn("Mail queue full, password reset dropped")
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// berjalan di worker mailer setelah response terkirim, ctx dibatasi MAIL_JOB_TIMEOUT
+func sendPasswordReset(ctx context.Context, logger *slog.Logger, email string) {
+	var user model.User
+	err := config.DB.QueryRowContext(ctx, "SELECT id, name, email FROM users WHERE email = $1", email).Scan(&user.Id, &user.Name, &user.Email)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logger.Error("Failed to fetch user for password reset", "error", err)
+		}
+		return
+	}
+
+	token, err := utils.GenerateToken(32)
+	if err != nil {
+		logger.Error("Failed to generate reset token", "error", err)
+		return
+	}
+
+	// token lama yang belum dipakai dianggap tidak berlaku
+	_, err = config.DB.ExecContext(ctx, "UPDATE password_resets SET used_at = $1 WHERE user_id = $2 AND used_at IS NULL", time.Now(), user.Id)
+	if err != nil {
+		logger.Error("Failed to invalidate old reset tokens", "error", err)
+		return
+	}
+
+	ttl := config.GetEnvDuration("PASSWORD_RESET_TTL", time.Hour)
+	_, err = config.DB.ExecContext(ctx, `
+		INSERT INTO password_resets (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+	`, user.Id, utils.HashToken(token), time.Now().Add(ttl), time.Now())
+	if err != nil {
+		logger.Error("Failed to save reset token", "error", err)
+		return
+	}
+
+	resetUrl := fmt.Sprintf(config.GetEnv("PASSWORD_RESET_URL", "https://codetech.crx.my.id/reset-password?token=%s"), token)
+	err = mailer.Default.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to reset your password. The link expires in %s and can only be used once.\n\n%s\n\nIf you did not request a password reset, you can ignore this email.",
+			user.Name, ttl, resetUrl,
+		),
+	})
+	if err != nil {
+		logger.Error("Failed to send reset email", "error", err)
+	}
+}
+
+// reset password dengan token dari email
+func ResetPassword(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// cek token dulu tanpa transaksi, validasi password (termasuk cek breach) bisa memanggil API luar
+	tokenHash := utils.HashToken(req.Token)
+	var reset model.PasswordReset
+	var user model.User
+	err = config.DB.QueryRowContext(ctx, `
+		SELECT pr.user_id, u.name, u.email
+		FROM password_resets pr
+		JOIN users u ON u.id = pr.user_id
+		WHERE pr.token_hash = $1 AND pr.used_at IS NULL AND pr.expires_at > $2
+	`, tokenHash, time.Now()).Scan(&reset.UserId, &user.Name, &user.Email)
+
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	// cek password policy dan pemakaian ulang password lama
+	if errors := utils.ValidatePassword(ctx, req.Password, user.Email, user.Name); len(errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	reused, err := utils.IsPasswordReused(ctx, reset.UserId, req.Password)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if reused {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password has been used recently"})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		utils.ServerError(c, "Failed to hash password", err)
+		return
+	}
+
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	defer tx.Rollback()
+
+	// tandai token sudah dipakai, gagal jika token sudah dipakai request lain di antara cek di atas
+	err = tx.QueryRowContext(ctx, `
+		UPDATE password_resets
+		SET used_at = $1
+		WHERE token_hash = $2 AND user_id = $3 AND used_at IS NULL AND expires_at > $1
+		RETURNING id
+	`, time.Now(), tokenHash, reset.UserId).Scan(&reset.Id)
+
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	if err := utils.SavePasswordHistory(ctx, tx, reset.UserId); err != nil {
+		utils.ServerError(c, "Failed to save password history", err)
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET password = $1, updated_at = $2 WHERE id = $3", hashedPassword, time.Now(), reset.UserId)
+	if err != nil {
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	// semua sesi lama dicabut setelah password diganti
+	if err := revokeUserSessions(ctx, reset.UserId, ""); err != nil {
+		utils.Logger(c).Error("Failed to revoke sessions", "error", err)
+	}
+
+	utils.RecordAudit(c, "password_reset", "users", &reset.UserId, nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
+}
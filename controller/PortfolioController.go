@@ -3,7 +3,6 @@ package controller
 import (
 	"database/sql"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -19,11 +19,12 @@ import (
 
 // getAllData
 func GetAllPortfolio(c *gin.Context) {
+	ctx := c.Request.Context()
 	var portfolios []model.Portfolio
 
-	rows, err := config.DB.Query("SELECT id, title, url, image, created_at, updated_at FROM portfolios")
+	rows, err := config.DB.QueryContext(ctx, "SELECT id, title, url, image, created_at, updated_at FROM portfolios")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -34,7 +35,7 @@ func GetAllPortfolio(c *gin.Context) {
 	for rows.Next() {
 		var portfolio model.Portfolio
 		if err := rows.Scan(&portfolio.Id, &portfolio.Title, &portfolio.Url, &portfolio.Image, &portfolio.CreatedAt, &portfolio.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
 		portfolios = append(portfolios, portfolio)
@@ -47,6 +48,7 @@ func GetAllPortfolio(c *gin.Context) {
 
 // create data
 func CreatePortfolio(c *gin.Context) {
+	ctx := c.Request.Context()
 	title := c.PostForm("title")
 	url := c.PostForm("url")
 
@@ -76,21 +78,26 @@ func CreatePortfolio(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
 		INSERT INTO portfolios (title, url, image, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5)
-	`, title, url, "/uploads/"+filename, time.Now(), time.Now())
+		RETURNING id
+	`, title, url, "/uploads/"+filename, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -98,6 +105,7 @@ func CreatePortfolio(c *gin.Context) {
 
 // update data
 func UpdatePortfolio(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -127,12 +135,12 @@ func UpdatePortfolio(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan image lama
 	var oldImage string
-	err = config.DB.QueryRow("SELECT image FROM portfolios WHERE id = $1", id).Scan(&oldImage)
+	err = config.DB.QueryRowContext(ctx, "SELECT image FROM portfolios WHERE id = $1", id).Scan(&oldImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Portfolio not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing portfolio", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing portfolio", err)
 		return
 	}
 
@@ -144,8 +152,8 @@ func UpdatePortfolio(c *gin.Context) {
 		os.MkdirAll("uploads", os.ModePerm)
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := filepath.Join("uploads", filename)
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 		newImagePath = "/uploads/" + filename
@@ -155,22 +163,22 @@ func UpdatePortfolio(c *gin.Context) {
 			_, oldFileName := filepath.Split(oldImage)
 			oldFilePath := filepath.Join("uploads", oldFileName)
 			if _, err := os.Stat(oldFilePath); err == nil {
-				if err := os.Remove(oldFilePath); err != nil {
-					log.Printf("Failed to delete old image: %s", err)
+				if err := utils.RemoveUpload(c, oldFilePath); err != nil {
+					utils.Logger(c).Error("Failed to delete old image", "error", err)
 				}
 			}
 		}
 	}
 
 	// Update data di PostgreSQL
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		UPDATE portfolios
 		SET title = $1, url = $2, image = $3, updated_at = $4
 		WHERE id = $5
 	`, title, url, newImagePath, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
@@ -181,6 +189,7 @@ func UpdatePortfolio(c *gin.Context) {
 
 // delete data
 func DeletePortfolio(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -190,12 +199,22 @@ func DeletePortfolio(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan image lama
 	var oldImage string
-	err = config.DB.QueryRow("SELECT image FROM portfolios WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
+	err = config.DB.QueryRowContext(ctx, "SELECT image FROM portfolios WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Portfolio not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing portfolio", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing portfolio", err)
+		return
+	}
+
+	_, err = config.DB.ExecContext(ctx, `
+		DELETE FROM portfolios
+		WHERE id = $1
+	`, id)
+
+	if err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
@@ -204,23 +223,12 @@ func DeletePortfolio(c *gin.Context) {
 		_, imageFile := filepath.Split(oldImage)
 		imagePath := filepath.Join("uploads", imageFile)
 		if _, err := os.Stat(imagePath); err == nil {
-			if err := os.Remove(imagePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image", "detail": err.Error()})
-				return
+			if err := utils.RemoveUpload(c, imagePath); err != nil {
+				utils.Logger(c).Error("Failed to delete image", "error", err, "path", imagePath)
 			}
 		}
 	}
 
-	_, err = config.DB.Exec(`
-		DELETE FROM portfolios
-		WHERE id = $1
-	`, id)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
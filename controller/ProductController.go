@@ -11,6 +11,7 @@ import (
 
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -18,11 +19,12 @@ import (
 
 // get all data
 func GetAllProduct(c *gin.Context) {
+	ctx := c.Request.Context()
 	var products []model.Product
 
-	rows, err := config.DB.Query("SELECT id, title, description, price, discount, type, icon, created_at, updated_at FROM products")
+	rows, err := config.DB.QueryContext(ctx, "SELECT id, title, description, price, discount, type, icon, created_at, updated_at FROM products")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -33,7 +35,7 @@ func GetAllProduct(c *gin.Context) {
 	for rows.Next() {
 		var product model.Product
 		if err := rows.Scan(&product.Id, &product.Title, &product.Description, &product.Price, &product.Discount, &product.Type, &product.Icon, &product.CreatedAt, &product.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
 		products = append(products, product)
@@ -46,6 +48,7 @@ func GetAllProduct(c *gin.Context) {
 
 // create data
 func CreateProduct(c *gin.Context) {
+	ctx := c.Request.Context()
 	// Ambil form input
 	title := c.PostForm("title")
 	description := c.PostForm("description")
@@ -93,15 +96,15 @@ func CreateProduct(c *gin.Context) {
 	if err == nil {
 		err := os.MkdirAll("uploads", os.ModePerm)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create uploads directory"})
+			utils.ServerError(c, "Failed to create uploads directory", err)
 			return
 		}
 
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := "uploads/" + filename
 
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 
@@ -109,19 +112,21 @@ func CreateProduct(c *gin.Context) {
 	}
 
 	// Simpan ke database
-	_, err = config.DB.Exec(`
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
 		INSERT INTO products (title, description, price, discount, type, icon, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`, title, description, price, discount, typeProduct, icon, time.Now(), time.Now())
+		RETURNING id
+	`, title, description, price, discount, typeProduct, icon, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":  "Failed to insert data",
-			"detail": err.Error(),
-		})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
 	// Response sukses
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Product created successfully",
@@ -130,6 +135,7 @@ func CreateProduct(c *gin.Context) {
 
 // update data
 func UpdateProduct(c *gin.Context) {
+	ctx := c.Request.Context()
 	// Ambil ID dari path parameter
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -180,12 +186,12 @@ func UpdateProduct(c *gin.Context) {
 
 	// Ambil data lama (icon lama)
 	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM products WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
+	err = config.DB.QueryRowContext(ctx, "SELECT icon FROM products WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch product", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch product", err)
 		return
 	}
 
@@ -197,7 +203,7 @@ func UpdateProduct(c *gin.Context) {
 		// Buat folder upload jika belum ada
 		err := os.MkdirAll("uploads", os.ModePerm)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create uploads folder"})
+			utils.ServerError(c, "Failed to create uploads folder", err)
 			return
 		}
 
@@ -205,8 +211,8 @@ func UpdateProduct(c *gin.Context) {
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := filepath.Join("uploads", filename)
 
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 
@@ -218,20 +224,20 @@ func UpdateProduct(c *gin.Context) {
 			_, imageFile := filepath.Split(oldIcon)
 			imagePath := filepath.Join("uploads", imageFile)
 			if _, err := os.Stat(imagePath); err == nil {
-				_ = os.Remove(imagePath) // Error diabaikan agar update tetap lanjut
+				_ = utils.RemoveUpload(c, imagePath) // Error diabaikan agar update tetap lanjut
 			}
 		}
 	}
 
 	// Update data
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		UPDATE products
 		SET title = $1, description = $2, price = $3, discount = $4, type = $5, icon = $6, updated_at = $7
 		WHERE id = $8
 	`, title, description, price, discount, typeProduct, iconPath, time.Now(), id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
@@ -240,6 +246,7 @@ func UpdateProduct(c *gin.Context) {
 
 // delete data
 func DeleteProduct(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -249,12 +256,18 @@ func DeleteProduct(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan icon lama
 	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM products WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
+	err = config.DB.QueryRowContext(ctx, "SELECT icon FROM products WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing product", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing product", err)
+		return
+	}
+
+	_, err = config.DB.ExecContext(ctx, "DELETE FROM products WHERE id = $1", sql.Named("p1", id))
+	if err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
@@ -263,19 +276,12 @@ func DeleteProduct(c *gin.Context) {
 		_, imageFile := filepath.Split(oldIcon)
 		imagePath := filepath.Join("uploads", imageFile)
 		if _, err := os.Stat(imagePath); err == nil {
-			if err := os.Remove(imagePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image", "detail": err.Error()})
-				return
+			if err := utils.RemoveUpload(c, imagePath); err != nil {
+				utils.Logger(c).Error("Failed to delete image", "error", err, "path", imagePath)
 			}
 		}
 	}
 
-	_, err = config.DB.Exec("DELETE FROM products WHERE id = $1", sql.Named("p1", id))
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
-		return
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
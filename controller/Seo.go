@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gin-gonic/gin"
+)
+
+// field SEO dari form admin, field yang tidak dikirim tetap memakai nilai lama
+func seoFromForm(c *gin.Context, current model.Seo) model.Seo {
+	fields := map[string]*string{
+		"meta_title":       &current.MetaTitle,
+		"meta_description": &current.MetaDescription,
+		"canonical_url":    &current.CanonicalUrl,
+		"og_image":         &current.OgImage,
+		"robots":           &current.Robots,
+	}
+	for name, field := range fields {
+		if value, ok := c.GetPostForm(name); ok {
+			*field = strings.TrimSpace(value)
+		}
+	}
+	return current
+}
@@ -9,8 +9,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/seo"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -19,11 +22,15 @@ import (
 
 // get all data
 func GetAllServices(c *gin.Context) {
+	ctx := c.Request.Context()
 	var services []model.Service
 
-	rows, err := config.DB.Query("SELECT id, title, slug, description, icon, created_at, updated_at FROM services")
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT id, title, slug, description, icon, meta_title, meta_description, canonical_url, og_image, robots, created_at, updated_at
+		FROM services
+	`)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -33,10 +40,13 @@ func GetAllServices(c *gin.Context) {
 
 	for rows.Next() {
 		var service model.Service
-		if err := rows.Scan(&service.Id, &service.Title, &service.Slug, &service.Description, &service.Icon, &service.CreatedAt, &service.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		if err := rows.Scan(&service.Id, &service.Title, &service.Slug, &service.Description, &service.Icon,
+			&service.Seo.MetaTitle, &service.Seo.MetaDescription, &service.Seo.CanonicalUrl, &service.Seo.OgImage, &service.Seo.Robots,
+			&service.CreatedAt, &service.UpdatedAt); err != nil {
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
+		service.Seo = seo.Resolve(service.Seo, "service", service.Slug, service.Title, service.Description, service.Icon)
 		services = append(services, service)
 	}
 
@@ -45,8 +55,35 @@ func GetAllServices(c *gin.Context) {
 	})
 }
 
+// detail service berdasarkan slug
+func GetServiceBySlug(c *gin.Context) {
+	ctx := c.Request.Context()
+	var service model.Service
+
+	err := config.DB.QueryRowContext(ctx, `
+		SELECT id, title, slug, description, icon, meta_title, meta_description, canonical_url, og_image, robots, created_at, updated_at
+		FROM services WHERE slug = $1
+	`, c.Param("slug")).Scan(&service.Id, &service.Title, &service.Slug, &service.Description, &service.Icon,
+		&service.Seo.MetaTitle, &service.Seo.MetaDescription, &service.Seo.CanonicalUrl, &service.Seo.OgImage, &service.Seo.Robots,
+		&service.CreatedAt, &service.UpdatedAt)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		return
+	}
+	if err != nil {
+		utils.ServerError(c, "Failed to fetch data", err)
+		return
+	}
+	service.Seo = seo.Resolve(service.Seo, "service", service.Slug, service.Title, service.Description, service.Icon)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": service,
+	})
+}
+
 // create data
 func CreateService(c *gin.Context) {
+	ctx := c.Request.Context()
 	title := c.PostForm("title")
 	description := c.PostForm("description")
 	file, err := c.FormFile("icon")
@@ -76,23 +113,32 @@ func CreateService(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
 	icon := "/uploads/" + filename
+	meta := seoFromForm(c, model.Seo{})
 
-	_, err = config.DB.Exec(`
-		INSERT INTO services (title, slug, description, icon, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`, title, slug.Make(title), description, icon, time.Now(), time.Now())
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
+		INSERT INTO services (title, slug, description, icon, meta_title, meta_description, canonical_url, og_image, robots, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, title, slug.Make(title), description, icon,
+		meta.MetaTitle, meta.MetaDescription, meta.CanonicalUrl, meta.OgImage, meta.Robots, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	cache.Invalidate("services", "sitemap")
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -100,6 +146,7 @@ func CreateService(c *gin.Context) {
 
 // update data
 func UpdateService(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -129,14 +176,19 @@ func UpdateService(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan icon lama
 	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM services WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
+	var meta model.Seo
+	err = config.DB.QueryRowContext(ctx,
+		"SELECT icon, meta_title, meta_description, canonical_url, og_image, robots FROM services WHERE id = $1",
+		sql.Named("p1", id),
+	).Scan(&oldIcon, &meta.MetaTitle, &meta.MetaDescription, &meta.CanonicalUrl, &meta.OgImage, &meta.Robots)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing service", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing service", err)
 		return
 	}
+	meta = seoFromForm(c, meta)
 
 	iconPath := oldIcon // default: gunakan icon lama
 
@@ -146,8 +198,8 @@ func UpdateService(c *gin.Context) {
 		os.MkdirAll("uploads", os.ModePerm)
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
 		savePath := "uploads/" + filename
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		if err := utils.SaveUpload(c, file, savePath); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 		iconPath = "/uploads/" + filename
@@ -158,24 +210,28 @@ func UpdateService(c *gin.Context) {
 		_, iconFile := filepath.Split(oldIcon)
 		iconPath := filepath.Join("uploads", iconFile)
 		if _, err := os.Stat(iconPath); err == nil {
-			if err := os.Remove(iconPath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image", "detail": err.Error()})
+			if err := utils.RemoveUpload(c, iconPath); err != nil {
+				utils.ServerError(c, "Failed to delete image", err)
 				return
 			}
 		}
 	}
 
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		UPDATE services
-		SET title = $1, slug = $2, description = $3, icon = $4, updated_at = $5
-		WHERE id = $6
-	`, title, slug.Make(title), description, iconPath, time.Now(), id)
+		SET title = $1, slug = $2, description = $3, icon = $4, updated_at = $5,
+			meta_title = $6, meta_description = $7, canonical_url = $8, og_image = $9, robots = $10
+		WHERE id = $11
+	`, title, slug.Make(title), description, iconPath, time.Now(),
+		meta.MetaTitle, meta.MetaDescription, meta.CanonicalUrl, meta.OgImage, meta.Robots, id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to update data", err)
 		return
 	}
 
+	cache.Invalidate("services", "sitemap")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data updated successfully",
 	})
@@ -183,6 +239,7 @@ func UpdateService(c *gin.Context) {
 
 // delete data
 func DeleteService(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -192,37 +249,38 @@ func DeleteService(c *gin.Context) {
 
 	// Ambil data lama untuk dapatkan icon lama
 	var oldIcon string
-	err = config.DB.QueryRow("SELECT icon FROM services WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
+	err = config.DB.QueryRowContext(ctx, "SELECT icon FROM services WHERE id = $1", sql.Named("p1", id)).Scan(&oldIcon)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
 		return
 	} else if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch existing service", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch existing service", err)
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	_, err = config.DB.ExecContext(ctx, `
 		DELETE FROM services
 		WHERE id = $1
 	`, id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
-	// Hapus file gambar jika ada
+	// Hapus file gambar jika ada, data sudah terhapus jadi kegagalan cukup dicatat
 	if oldIcon != "" {
 		_, imageFile := filepath.Split(oldIcon)
 		imagePath := filepath.Join("uploads", imageFile)
 		if _, err := os.Stat(imagePath); err == nil {
-			if err := os.Remove(imagePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete image", "detail": err.Error()})
-				return
+			if err := utils.RemoveUpload(c, imagePath); err != nil {
+				utils.Logger(c).Error("Failed to delete image", "error", err, "path", imagePath)
 			}
 		}
 	}
 
+	cache.Invalidate("services", "sitemap")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
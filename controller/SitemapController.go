@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/seo"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/gosimple/slug"
+)
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+// konten dengan robots noindex / none tidak dimasukkan ke sitemap
+const sitemapIndexable = "robots NOT ILIKE '%noindex%' AND robots NOT ILIKE '%none%'"
+
+// sitemap.xml, berubah menjadi sitemap index jika url lebih dari 50.000
+func Sitemap(c *gin.Context) {
+	entries, err := sitemapEntries(c.Request.Context())
+	if err != nil {
+		utils.ServerError(c, "Failed to generate sitemap", err)
+		return
+	}
+
+	if seo.SitemapPages(len(entries)) > 1 {
+		c.Data(http.StatusOK, xmlContentType, seo.Index(entries))
+		return
+	}
+	body, _ := seo.URLSet(entries, 1)
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+// bagian sitemap yang dirujuk sitemap index, contoh /sitemaps/2.xml
+func SitemapPage(c *gin.Context) {
+	name := c.Param("name")
+	page, err := strconv.Atoi(strings.TrimSuffix(name, ".xml"))
+	if err != nil || !strings.HasSuffix(name, ".xml") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	entries, err := sitemapEntries(c.Request.Context())
+	if err != nil {
+		utils.ServerError(c, "Failed to generate sitemap", err)
+		return
+	}
+
+	body, ok := seo.URLSet(entries, page)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+func RobotsTxt(c *gin.Context) {
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", seo.RobotsTxt())
+}
+
+// url publik halaman, service, artikel dan kategori artikel dengan updated_at sebagai lastmod
+func sitemapEntries(ctx context.Context) ([]seo.Entry, error) {
+	entries := []seo.Entry{{Loc: seo.SiteURL() + "/"}}
+
+	sources := []struct {
+		kind  string
+		query string
+	}{
+		{"page", "SELECT slug, updated_at FROM pages WHERE " + sitemapIndexable + " ORDER BY id"},
+		{"service", "SELECT slug, updated_at FROM services WHERE " + sitemapIndexable + " ORDER BY id"},
+		{"article", "SELECT slug, updated_at FROM articles WHERE " + sitemapIndexable + " ORDER BY updated_at DESC, id DESC"},
+		// kategori hanya yang punya artikel, slug dibuat dari nama kategori
+		{"category", `
+			SELECT c.category, GREATEST(c.updated_at, MAX(a.updated_at))
+			FROM category_articles c
+			JOIN articles a ON a.category_id = c.id
+			GROUP BY c.id
+			ORDER BY c.id
+		`},
+	}
+
+	for _, source := range sources {
+		rows, err := config.DB.QueryContext(ctx, source.query)
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var entry seo.Entry
+			var name string
+			if err := rows.Scan(&name, &entry.LastMod); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if source.kind == "category" {
+				name = slug.Make(name)
+			}
+			entry.Loc = seo.URL(source.kind, name)
+			entries = append(entries, entry)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const recoveryCodeCount = 10
+
+// mulai enrollment 2FA, secret baru belum aktif sampai diverifikasi
+func SetupTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userId := c.GetInt("user_id")
+
+	var email string
+	var enabled bool
+	err := config.DB.QueryRowContext(ctx, `
+		SELECT u.email, tf.enabled_at IS NOT NULL
+		FROM users u
+		LEFT JOIN user_two_factors tf ON tf.user_id = u.id
+		WHERE u.id = $1
+	`, userId).Scan(&email, &enabled)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if enabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		utils.ServerError(c, "Failed to generate secret", err)
+		return
+	}
+
+	// secret disimpan terenkripsi
+	encrypted, err := utils.EncryptSecret(secret)
+	if err != nil {
+		utils.ServerError(c, "Failed to encrypt secret", err)
+		return
+	}
+
+	_, err = config.DB.ExecContext(ctx, `
+		INSERT INTO user_two_factors (user_id, secret, last_step, enabled_at, created_at)
+		VALUES ($1, $2, 0, NULL, $3)
+		ON CONFLICT (user_id) DO UPDATE SET secret = $2, last_step = 0, enabled_at = NULL, created_at = $3
+	`, userId, encrypted, time.Now())
+	if err != nil {
+		utils.ServerError(c, "Failed to insert data", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "2fa_setup")
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"secret":           secret,
+			"provisioning_uri": utils.TOTPProvisioningURI(config.GetEnv("TWO_FACTOR_ISSUER", "Codetech"), email, secret),
+		},
+	})
+}
+
+// verifikasi kode pertama, aktifkan 2FA dan buat recovery code
+func VerifyTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userId := c.GetInt("user_id")
+
+	var req model.TwoFactorCodeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	var twoFactor model.TwoFactor
+	err = config.DB.QueryRowContext(ctx, "SELECT secret, last_step, enabled_at FROM user_two_factors WHERE user_id = $1", userId).Scan(
+		&twoFactor.Secret, &twoFactor.LastStep, &twoFactor.EnabledAt,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor setup has not been started"})
+		return
+	} else if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if twoFactor.EnabledAt != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := utils.DecryptSecret(twoFactor.Secret)
+	if err != nil {
+		utils.ServerError(c, "Failed to decrypt secret", err)
+		return
+	}
+
+	step, ok := utils.ValidateTOTP(secret, req.Code, time.Now(), twoFactor.LastStep)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE user_two_factors SET enabled_at = $1, last_step = $2 WHERE user_id = $3", time.Now(), step, userId)
+	if err != nil {
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	codes, err := replaceRecoveryCodes(ctx, tx, userId)
+	if err != nil {
+		utils.ServerError(c, "Failed to generate recovery codes", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "2fa_enable")
+
+	// recovery code hanya ditampilkan sekali
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Two-factor authentication enabled",
+		"data": gin.H{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// matikan 2FA milik sendiri, butuh kode valid dan tidak berlaku untuk role yang diwajibkan
+func DisableTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	userId := c.GetInt("user_id")
+
+	if utils.TwoFactorRequired(c.GetString("role")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for your role"})
+		return
+	}
+
+	var req model.TwoFactorCodeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	valid, err := verifyTwoFactorCode(ctx, userId, req.Code)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+
+	if err := deleteTwoFactor(ctx, userId); err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "2fa_disable")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// reset 2FA user lain (khusus superadmin)
+func ResetUserTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+	if c.GetString("role") != "superadmin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only superadmin can reset two-factor authentication"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var exists bool
+	err = config.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := deleteTwoFactor(ctx, id); err != nil {
+		utils.ServerError(c, "Failed to delete data", err)
+		return
+	}
+
+	// sesi yang login dengan 2FA lama ikut dicabut
+	if err := revokeUserSessions(ctx, id, ""); err != nil {
+		utils.ServerError(c, "Failed to revoke sessions", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_action", "2fa_reset")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset successfully"})
+}
+
+// cek kode TOTP, jika gagal coba sebagai recovery code
+func verifyTwoFactorCode(ctx context.Context, userId int, code string) (bool, error) {
+	var twoFactor model.TwoFactor
+	err := config.DB.QueryRowContext(ctx, "SELECT secret, last_step FROM user_two_factors WHERE user_id = $1 AND enabled_at IS NOT NULL", userId).Scan(
+		&twoFactor.Secret, &twoFactor.LastStep,
+	)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	secret, err := utils.DecryptSecret(twoFactor.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	if step, ok := utils.ValidateTOTP(secret, code, time.Now(), twoFactor.LastStep); ok {
+		// simpan step terakhir agar kode tidak bisa dipakai ulang
+		result, err := config.DB.ExecContext(ctx, "UPDATE user_two_factors SET last_step = $1 WHERE user_id = $2 AND last_step < $1", step, userId)
+		if err != nil {
+			return false, err
+		}
+		rowsAffected, _ := result.RowsAffected()
+		return rowsAffected == 1, nil
+	}
+
+	result, err := config.DB.ExecContext(ctx, `
+		UPDATE user_recovery_codes
+		SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`, time.Now(), userId, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected == 1, nil
+}
+
+// buat ulang recovery code, kembalikan kode asli (plain) untuk ditampilkan sekali
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) ([]string, error) {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userId); err != nil {
+		return nil, err
+	}
+
+	codes := []string{}
+	for i := 0; i < recoveryCodeCount; i++ {
+		token, err := utils.GenerateToken(5)
+		if err != nil {
+			return nil, err
+		}
+		code := token[:5] + "-" + token[5:]
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_recovery_codes (user_id, code_hash, created_at)
+			VALUES ($1, $2, $3)
+		`, userId, utils.HashToken(normalizeRecoveryCode(code)), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+func deleteTwoFactor(ctx context.Context, userId int) error {
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_two_factors WHERE user_id = $1", userId); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
 	"github.com/gibranfajar/backend-codetech/model"
 	"github.com/gibranfajar/backend-codetech/utils"
@@ -19,11 +20,12 @@ import (
 
 // get all data
 func GetAllUser(c *gin.Context) {
+	ctx := c.Request.Context()
 	var users []model.UserResponse
 
-	rows, err := config.DB.Query("SELECT id, name, email, profile, role, created_at, updated_at FROM users")
+	rows, err := config.DB.QueryContext(ctx, "SELECT id, name, email, profile, role, created_at, updated_at FROM users")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -34,7 +36,7 @@ func GetAllUser(c *gin.Context) {
 	for rows.Next() {
 		var user model.UserResponse
 		if err := rows.Scan(&user.Id, &user.Name, &user.Email, &user.Profile, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+			utils.ServerError(c, "Failed to fetch data", err)
 			return
 		}
 		users = append(users, user)
@@ -47,6 +49,7 @@ func GetAllUser(c *gin.Context) {
 
 // get data where is login user with middleware
 func GetUser(c *gin.Context) {
+	ctx := c.Request.Context()
 	var user model.UserResponse
 
 	id, ok := c.MustGet("user_id").(int)
@@ -55,12 +58,12 @@ func GetUser(c *gin.Context) {
 		return
 	}
 
-	err := config.DB.QueryRow("SELECT id, name, email, profile, role, created_at, updated_at FROM users WHERE id = $1", sql.Named("p1", id)).Scan(
+	err := config.DB.QueryRowContext(ctx, "SELECT id, name, email, profile, role, created_at, updated_at FROM users WHERE id = $1", sql.Named("p1", id)).Scan(
 		&user.Id, &user.Name, &user.Email, &user.Profile, &user.Role, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 
@@ -71,6 +74,7 @@ func GetUser(c *gin.Context) {
 
 // create data
 func CreateUser(c *gin.Context) {
+	ctx := c.Request.Context()
 
 	var req model.UserRequest
 
@@ -96,16 +100,32 @@ func CreateUser(c *gin.Context) {
 	password := c.PostForm("password")
 	role := c.PostForm("role")
 
+	// pendaftaran publik selalu role user, role lain hanya boleh diberikan admin sesuai tingkatannya
+	if _, authenticated := c.Get("user_id"); !authenticated {
+		role = "user"
+	} else if role == "" {
+		role = "user"
+	} else if role != "user" && !utils.CanAssignRole(c.GetString("role"), role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to assign this role"})
+		return
+	}
+
+	// cek password policy
+	if errors := utils.ValidatePassword(ctx, password, email, name); len(errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
 	// hash password
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		utils.ServerError(c, "Failed to hash password", err)
 		return
 	}
 
 	// check apakah data sudah ada atau tidak
 	var user model.User
-	err = config.DB.QueryRow("SELECT id FROM users WHERE email = $1", sql.Named("p1", email)).Scan(&user.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id FROM users WHERE email = $1", sql.Named("p1", email)).Scan(&user.Id)
 	if err == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Data already exists"})
 		return
@@ -121,21 +141,31 @@ func CreateUser(c *gin.Context) {
 	os.MkdirAll("uploads", os.ModePerm)
 	filename := uuid.New().String() + filepath.Ext(file.Filename)
 	savePath := "uploads/" + filename
-	if err := c.SaveUploadedFile(file, savePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+	if err := utils.SaveUpload(c, file, savePath); err != nil {
+		utils.ServerError(c, "Failed to upload image", err)
 		return
 	}
 
-	_, err = config.DB.Exec(`
+	var newId int
+	err = config.DB.QueryRowContext(ctx, `
 		INSERT INTO users (name, email, password, profile, role, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, name, email, hashedPassword, "/uploads/"+filename, role, time.Now(), time.Now())
+		RETURNING id
+	`, name, email, hashedPassword, "/uploads/"+filename, role, time.Now(), time.Now()).Scan(&newId)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to insert data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to insert data", err)
 		return
 	}
 
+	// id baru untuk audit log
+	c.Set("audit_resource_id", newId)
+
+	// profil publik penulis
+	if err := createAuthorProfile(ctx, newId, name); err != nil {
+		utils.Logger(c).Error("Failed to create author profile", "error", err)
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Data created successfully",
 	})
@@ -144,6 +174,7 @@ func CreateUser(c *gin.Context) {
 
 // update data
 func UpdateUser(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -173,22 +204,45 @@ func UpdateUser(c *gin.Context) {
 	role := c.PostForm("role")
 
 	// Cek apakah user ada
-	var exists bool
-	err = config.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)", id).Scan(&exists)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
-		return
-	}
-	if !exists {
+	var currentRole string
+	err = config.DB.QueryRowContext(ctx, "SELECT role FROM users WHERE id = $1", id).Scan(&currentRole)
+	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
+	} else if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	// user biasa tidak boleh mengubah user lain atau role
+	if !utils.IsPrivileged(c) {
+		if id != c.GetInt("user_id") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this user"})
+			return
+		}
+		if role != currentRole {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change the role"})
+			return
+		}
+	}
+
+	// admin tidak boleh mengubah user dengan role lebih tinggi atau menaikkan role melebihi role-nya
+	if utils.IsPrivileged(c) {
+		if utils.RoleRank(currentRole) > utils.RoleRank(c.GetString("role")) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to update this user"})
+			return
+		}
+		if role != currentRole && !utils.CanAssignRole(c.GetString("role"), role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to assign this role"})
+			return
+		}
 	}
 
 	// Cek email duplicate (kecuali milik user ini)
 	var emailExists bool
-	err = config.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND id != $2)", email, id).Scan(&emailExists)
+	err = config.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND id != $2)", email, id).Scan(&emailExists)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		utils.ServerError(c, "Database error", err)
 		return
 	}
 	if emailExists {
@@ -196,35 +250,68 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
+	// cek password policy dan pemakaian ulang password lama sebelum menyentuh file
+	var hashedPassword string
+	if password != "" {
+		if errors := utils.ValidatePassword(ctx, password, email, name); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+			return
+		}
+
+		reused, err := utils.IsPasswordReused(ctx, id, password)
+		if err != nil {
+			utils.ServerError(c, "Database error", err)
+			return
+		}
+		if reused {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Password has been used recently"})
+			return
+		}
+
+		hashedPassword, err = utils.HashPassword(password)
+		if err != nil {
+			utils.ServerError(c, "Failed to hash password", err)
+			return
+		}
+	}
+
 	// Ambil gambar lama
 	var oldImage string
-	err = config.DB.QueryRow("SELECT profile FROM users WHERE id = $1", id).Scan(&oldImage)
+	err = config.DB.QueryRowContext(ctx, "SELECT profile FROM users WHERE id = $1", id).Scan(&oldImage)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get old profile"})
+		utils.ServerError(c, "Failed to get old profile", err)
 		return
 	}
 
-	// Upload file baru jika ada
-	var profilePath string
+	// Upload file baru jika ada, file lama baru dihapus setelah update berhasil
+	profilePath := oldImage
+	newUpload := ""
 	file, err := c.FormFile("profile")
 	if err == nil {
 		os.MkdirAll("uploads", os.ModePerm)
 		filename := uuid.New().String() + filepath.Ext(file.Filename)
-		savePath := "uploads/" + filename
-		if err := c.SaveUploadedFile(file, savePath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload image"})
+		newUpload = "uploads/" + filename
+		if err := utils.SaveUpload(c, file, newUpload); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
 			return
 		}
 		profilePath = "/uploads/" + filename
+	}
 
-		// Hapus file lama jika ada
-		if oldImage != "" {
-			_, oldFile := filepath.Split(oldImage)
-			os.Remove("uploads/" + oldFile)
+	// file baru dibuang jika update gagal
+	failed := func(msg string, err error) {
+		if newUpload != "" {
+			utils.RemoveUpload(c, newUpload)
 		}
-	} else {
-		profilePath = oldImage
+		utils.ServerError(c, msg, err)
+	}
+
+	tx, err := config.DB.BeginTx(ctx, nil)
+	if err != nil {
+		failed("Database error", err)
+		return
 	}
+	defer tx.Rollback()
 
 	// Siapkan query update
 	query := `
@@ -233,9 +320,8 @@ func UpdateUser(c *gin.Context) {
 	args := []interface{}{name, email, profilePath, role, time.Now()}
 
 	if password != "" {
-		hashedPassword, err := utils.HashPassword(password)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		if err := utils.SavePasswordHistory(ctx, tx, id); err != nil {
+			failed("Failed to save password history", err)
 			return
 		}
 		query += `, password = $6 WHERE id = $7`
@@ -245,17 +331,230 @@ func UpdateUser(c *gin.Context) {
 		args = append(args, id)
 	}
 
-	_, err = config.DB.Exec(query, args...)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		failed("Failed to update data", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		failed("Failed to update data", err)
+		return
+	}
+
+	// Hapus file lama jika diganti
+	if newUpload != "" && oldImage != "" {
+		_, oldFile := filepath.Split(oldImage)
+		utils.RemoveUpload(c, "uploads/"+oldFile)
+	}
+
+	// password diganti admin, semua sesi user dicabut (kecuali sesi pemanggil jika mengubah diri sendiri)
+	if password != "" {
+		exceptSession := ""
+		if id == c.GetInt("user_id") {
+			exceptSession = c.GetString("session_id")
+		}
+		if err := revokeUserSessions(ctx, id, exceptSession); err != nil {
+			utils.ServerError(c, "Failed to revoke sessions", err)
+			return
+		}
+	}
+
+	cache.Invalidate("articles")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
+}
+
+// update profil milik user yang sedang login (tanpa role)
+func UpdateMe(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.GetInt("user_id")
+
+	var req model.UserRequestUpdateMe
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// Cek email duplicate (kecuali milik user ini)
+	var emailExists bool
+	err = config.DB.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND id != $2)", req.Email, id).Scan(&emailExists)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update data", "detail": err.Error()})
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if emailExists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already exists"})
 		return
 	}
 
+	// Ambil gambar lama
+	var oldImage string
+	err = config.DB.QueryRowContext(ctx, "SELECT profile FROM users WHERE id = $1", id).Scan(&oldImage)
+	if err != nil {
+		utils.ServerError(c, "Failed to get old profile", err)
+		return
+	}
+
+	// Upload file baru jika ada, file lama baru dihapus setelah update berhasil
+	profilePath := oldImage
+	newUpload := ""
+	file, err := c.FormFile("profile")
+	if err == nil {
+		os.MkdirAll("uploads", os.ModePerm)
+		filename := uuid.New().String() + filepath.Ext(file.Filename)
+		newUpload = "uploads/" + filename
+		if err := utils.SaveUpload(c, file, newUpload); err != nil {
+			utils.ServerError(c, "Failed to upload image", err)
+			return
+		}
+		profilePath = "/uploads/" + filename
+	}
+
+	_, err = config.DB.ExecContext(ctx, `
+		UPDATE users
+		SET name = $1, email = $2, profile = $3, updated_at = $4
+		WHERE id = $5
+	`, req.Name, req.Email, profilePath, time.Now(), id)
+
+	if err != nil {
+		if newUpload != "" {
+			utils.RemoveUpload(c, newUpload)
+		}
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	if newUpload != "" && oldImage != "" {
+		_, oldFile := filepath.Split(oldImage)
+		utils.RemoveUpload(c, "uploads/"+oldFile)
+	}
+
+	// id untuk audit log
+	c.Set("audit_resource_id", id)
+
+	cache.Invalidate("articles")
+
 	c.JSON(http.StatusOK, gin.H{"message": "Data updated successfully"})
 }
 
+// ganti password milik user yang sedang login, sesi lain dicabut
+func ChangePassword(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.GetInt("user_id")
+
+	var req model.ChangePasswordRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validasi menggunakan validator
+	err := config.Validate.Struct(req)
+	if err != nil {
+		errors := []string{}
+		for _, err := range err.(validator.ValidationErrors) {
+			errors = append(errors, fmt.Sprintf("%s is %s", err.Field(), err.Tag()))
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	// tebakan password lama dibatasi seperti login, agar token curian tidak bisa brute force password
+	passwordKey := fmt.Sprintf("password:%d", id)
+	remaining, err := loginLockRemaining(ctx, passwordKey)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if remaining > 0 {
+		tooManyLoginAttempts(c, remaining)
+		return
+	}
+
+	var user model.User
+	err = config.DB.QueryRowContext(ctx, "SELECT name, email, password FROM users WHERE id = $1", id).Scan(&user.Name, &user.Email, &user.Password)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, user.Password) {
+		_, lockedUntil, err := registerLoginFailure(ctx, passwordKey, config.GetEnvInt("LOGIN_MAX_ATTEMPTS", 5))
+		if err != nil {
+			utils.Logger(c).Error("Failed to register login failure", "error", err)
+		}
+		if lockedUntil != nil {
+			utils.RecordAudit(c, "lockout", "users", &id, nil, nil)
+			tooManyLoginAttempts(c, time.Until(*lockedUntil))
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	if err := clearLoginThrottle(ctx, passwordKey); err != nil {
+		utils.Logger(c).Error("Failed to clear login throttle", "error", err)
+	}
+
+	// cek password policy dan pemakaian ulang password lama
+	if errors := utils.ValidatePassword(ctx, req.Password, user.Email, user.Name); len(errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": errors})
+		return
+	}
+
+	reused, err := utils.IsPasswordReused(ctx, id, req.Password)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+	if reused {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password has been used recently"})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		utils.ServerError(c, "Failed to hash password", err)
+		return
+	}
+
+	if err := utils.SavePasswordHistory(ctx, config.DB, id); err != nil {
+		utils.ServerError(c, "Failed to save password history", err)
+		return
+	}
+
+	_, err = config.DB.ExecContext(ctx, "UPDATE users SET password = $1, updated_at = $2 WHERE id = $3", hashedPassword, time.Now(), id)
+	if err != nil {
+		utils.ServerError(c, "Failed to update data", err)
+		return
+	}
+
+	// sesi lain dicabut, sesi yang sedang dipakai tetap aktif
+	if err := revokeUserSessions(ctx, id, c.GetString("session_id")); err != nil {
+		utils.ServerError(c, "Failed to revoke sessions", err)
+		return
+	}
+
+	// action audit log
+	c.Set("audit_resource_id", id)
+	c.Set("audit_action", "password_change")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed successfully"})
+}
+
 // delete data
 func DeleteUser(c *gin.Context) {
+	ctx := c.Request.Context()
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
@@ -263,28 +562,56 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
+	// hanya admin/superadmin yang boleh menghapus user, dan tidak boleh menghapus akun sendiri
+	if !utils.IsPrivileged(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete users"})
+		return
+	}
+	if id == c.GetInt("user_id") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You cannot delete your own account"})
+		return
+	}
+
 	// check apakah data ada dengan id tersebut
 	var user model.User
-	err = config.DB.QueryRow("SELECT id FROM users WHERE id = $1", sql.Named("p1", id)).Scan(&user.Id)
+	err = config.DB.QueryRowContext(ctx, "SELECT id, role FROM users WHERE id = $1", sql.Named("p1", id)).Scan(&user.Id, &user.Role)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Data not found"})
 		return
+	} else if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
+	}
+
+	// user dengan role setara atau lebih tinggi dari pemanggil tidak boleh dihapus
+	if utils.RoleRank(user.Role) >= utils.RoleRank(c.GetString("role")) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete this user"})
+		return
 	}
 
-	// hapus file lama jika ada
 	var oldImage string
-	err = config.DB.QueryRow("SELECT profile FROM users WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
-	if err == nil && oldImage != "" {
-		_, filename := filepath.Split(oldImage)
-		os.Remove("uploads/" + filename)
+	err = config.DB.QueryRowContext(ctx, "SELECT profile FROM users WHERE id = $1", sql.Named("p1", id)).Scan(&oldImage)
+	if err != nil {
+		utils.ServerError(c, "Database error", err)
+		return
 	}
 
-	_, err = config.DB.Exec("DELETE FROM users WHERE id = $1", sql.Named("p1", id))
+	_, err = config.DB.ExecContext(ctx, "DELETE FROM users WHERE id = $1", sql.Named("p1", id))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to delete data", err)
 		return
 	}
 
+	// hapus file lama jika ada
+	if oldImage != "" {
+		_, filename := filepath.Split(oldImage)
+		if err := utils.RemoveUpload(c, "uploads/"+filename); err != nil {
+			utils.Logger(c).Error("Failed to delete profile image", "error", err)
+		}
+	}
+
+	cache.Invalidate("articles")
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Data deleted successfully",
 	})
@@ -292,23 +619,25 @@ func DeleteUser(c *gin.Context) {
 
 // get data where not admin
 func GetUserNotAdmin(c *gin.Context) {
-	var users []model.UserResponse
-
-	rows, err := config.DB.Query(`
-		SELECT id, name, email, profile, role, created_at, updated_at
-		FROM users
-		WHERE role != 'admin' AND role != 'superadmin'
+	ctx := c.Request.Context()
+	var users []model.PublicUserResponse
+
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT u.id, u.name, COALESCE(ap.slug, ''), COALESCE(NULLIF(ap.avatar, ''), u.profile)
+		FROM users u
+		LEFT JOIN author_profiles ap ON ap.user_id = u.id
+		WHERE u.role != 'admin' AND u.role != 'superadmin'
 	`)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch data", "detail": err.Error()})
+		utils.ServerError(c, "Failed to fetch data", err)
 		return
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var user model.UserResponse
-		if err := rows.Scan(&user.Id, &user.Name, &user.Email, &user.Profile, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan data", "detail": err.Error()})
+		var user model.PublicUserResponse
+		if err := rows.Scan(&user.Id, &user.Name, &user.Slug, &user.Profile); err != nil {
+			utils.ServerError(c, "Failed to scan data", err)
 			return
 		}
 		users = append(users, user)
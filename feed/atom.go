@@ -0,0 +1,92 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/model"
+)
+
+const AtomContentType = "application/atom+xml; charset=utf-8"
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	Id       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Id        string        `xml:"id"`
+	Title     string        `xml:"title"`
+	Published string        `xml:"published"`
+	Updated   string        `xml:"updated"`
+	Author    atomAuthor    `xml:"author"`
+	Category  *atomCategory `xml:"category"`
+	Links     []atomLink    `xml:"link"`
+	Summary   string        `xml:"summary"`
+	Content   atomContent   `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// feed Atom 1.0, thumbnail dikirim sebagai link rel=enclosure
+func Atom(channel Channel, articles []model.ResponseArticle) []byte {
+	doc := atomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		Lang:     channel.Language,
+		Id:       channel.Self,
+		Title:    channel.Title,
+		Subtitle: channel.Description,
+		Updated:  latestUpdate(articles).Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: channel.Self, Rel: "self", Type: "application/atom+xml"},
+			{Href: channel.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: []atomEntry{},
+	}
+
+	for _, article := range articles {
+		entry := atomEntry{
+			Id:        guid(article),
+			Title:     article.Title,
+			Published: article.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   article.UpdatedAt.UTC().Format(time.RFC3339),
+			Author:    atomAuthor{Name: article.Author.Name},
+			Links:     []atomLink{{Href: article.Seo.CanonicalUrl, Rel: "alternate", Type: "text/html"}},
+			Summary:   article.Excerpt,
+			Content:   atomContent{Type: "html", Value: absoluteContent(article.ContentHTML)},
+		}
+		if article.Category != "" {
+			entry.Category = &atomCategory{Term: article.Category}
+		}
+		if thumbnail := thumbnailEnclosure(article.Thumbnail); thumbnail != nil {
+			entry.Links = append(entry.Links, atomLink{Href: thumbnail.Url, Rel: "enclosure", Type: thumbnail.Type, Length: thumbnail.Length})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
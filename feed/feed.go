@@ -0,0 +1,75 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/model"
+	"github.com/gibranfajar/backend-codetech/seo"
+)
+
+// metadata channel, Self adalah url absolut feed ini untuk link rel=self
+type Channel struct {
+	Title       string
+	Description string
+	Language    string
+	Link        string
+	Self        string
+}
+
+// gambar di isi artikel memakai path relatif /uploads/..., feed reader butuh url absolut
+func absoluteContent(html string) string {
+	return strings.ReplaceAll(html, `src="/uploads/`, `src="`+seo.MediaURL("/uploads/"))
+}
+
+// guid stabil walau slug artikel berubah
+func guid(article model.ResponseArticle) string {
+	return fmt.Sprintf("%s/articles/%d", seo.SiteURL(), article.Id)
+}
+
+// thumbnail sebagai enclosure, ukuran file dibaca dari folder uploads
+type enclosure struct {
+	Url    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int64  `xml:"length,attr"`
+}
+
+func thumbnailEnclosure(thumbnail string) *enclosure {
+	if thumbnail == "" {
+		return nil
+	}
+
+	var length int64
+	if info, err := os.Stat(filepath.Join("uploads", filepath.Base(thumbnail))); err == nil {
+		length = info.Size()
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(thumbnail))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &enclosure{Url: seo.MediaURL(thumbnail), Type: contentType, Length: length}
+}
+
+// waktu artikel terakhir berubah, dipakai untuk lastBuildDate / updated
+func latestUpdate(articles []model.ResponseArticle) time.Time {
+	var latest time.Time
+	for _, article := range articles {
+		if article.UpdatedAt.After(latest) {
+			latest = article.UpdatedAt
+		}
+	}
+	if latest.IsZero() {
+		latest = time.Now()
+	}
+	return latest.UTC()
+}
+
+func marshalXML(v interface{}) []byte {
+	out, _ := xml.MarshalIndent(v, "", "  ")
+	return append([]byte(xml.Header), out...)
+}
@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/model"
+)
+
+const RSSContentType = "application/rss+xml; charset=utf-8"
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	Dc      string     `xml:"xmlns:dc,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title          string     `xml:"title"`
+	Link           string     `xml:"link"`
+	Guid           rssGuid    `xml:"guid"`
+	PubDate        string     `xml:"pubDate"`
+	Creator        string     `xml:"dc:creator"`
+	Category       string     `xml:"category,omitempty"`
+	Description    string     `xml:"description"`
+	ContentEncoded cdata      `xml:"content:encoded"`
+	Enclosure      *enclosure `xml:"enclosure"`
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// feed RSS 2.0 dengan isi lengkap di content:encoded
+func RSS(channel Channel, articles []model.ResponseArticle) []byte {
+	doc := rss{
+		Version: "2.0",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		Dc:      "http://purl.org/dc/elements/1.1/",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         channel.Title,
+			Link:          channel.Link,
+			Description:   channel.Description,
+			Language:      channel.Language,
+			LastBuildDate: latestUpdate(articles).Format(time.RFC1123Z),
+			Self:          atomLink{Href: channel.Self, Rel: "self", Type: "application/rss+xml"},
+			Items:         []rssItem{},
+		},
+	}
+
+	for _, article := range articles {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:          article.Title,
+			Link:           article.Seo.CanonicalUrl,
+			Guid:           rssGuid{IsPermaLink: false, Value: guid(article)},
+			PubDate:        article.CreatedAt.UTC().Format(time.RFC1123Z),
+			Creator:        article.Author.Name,
+			Category:       article.Category,
+			Description:    article.Excerpt,
+			ContentEncoded: cdata{Value: absoluteContent(article.ContentHTML)},
+			Enclosure:      thumbnailEnclosure(article.Thumbnail),
+		})
+	}
+	return marshalXML(doc)
+}
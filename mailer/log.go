@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogSender tidak mengirim email, hanya menulis ke file atau log (untuk local)
+type LogSender struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewLogSender(path string) *LogSender {
+	return &LogSender{Path: path}
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	entry := fmt.Sprintf("[%s] To: %s\nSubject: %s\n\n%s\n----\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+
+	if s.Path == "" {
+		slog.InfoContext(ctx, "Mail", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(entry)
+	return err
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender mengirim email, implementasi dipilih lewat env MAIL_DRIVER
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var Default Sender
+
+// antrian pengiriman di background, dikosongkan lewat Shutdown saat aplikasi berhenti
+var Jobs *Queue
+
+func InitMailer() {
+	switch config.GetEnv("MAIL_DRIVER", "log") {
+	case "smtp":
+		Default = NewSMTPSender(
+			config.GetEnv("SMTP_HOST", "localhost"),
+			config.GetEnvInt("SMTP_PORT", 587),
+			config.GetEnv("SMTP_USERNAME", ""),
+			config.GetEnv("SMTP_PASSWORD", ""),
+			config.GetEnv("MAIL_FROM", "no-reply@codetech.crx.my.id"),
+		)
+	default:
+		Default = NewLogSender(config.GetEnv("MAIL_LOG_FILE", ""))
+	}
+
+	Jobs = NewQueue(
+		config.GetEnvInt("MAIL_WORKERS", 2),
+		config.GetEnvInt("MAIL_QUEUE_SIZE", 100),
+		config.GetEnvDuration("MAIL_JOB_TIMEOUT", 30*time.Second),
+	)
+
+	slog.Info("Mailer ready", "driver", fmt.Sprintf("%T", Default))
+}
+
+// tunggu email yang masih di antrian terkirim
+func Shutdown(ctx context.Context) error {
+	if Jobs == nil {
+		return nil
+	}
+	return Jobs.Shutdown(ctx)
+}
@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Queue menjalankan pekerjaan email di background dengan jumlah worker dan kapasitas antrian terbatas,
+// setiap pekerjaan mendapat context dengan timeout sendiri
+type Queue struct {
+	jobs    chan func(ctx context.Context)
+	timeout time.Duration
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func NewQueue(workers, size int, timeout time.Duration) *Queue {
+	q := &Queue{
+		jobs:    make(chan func(ctx context.Context), size),
+		timeout: timeout,
+	}
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		ctx, cancel := context.WithTimeout(context.Background(), q.timeout)
+		job(ctx)
+		cancel()
+	}
+}
+
+// masukkan pekerjaan tanpa menunggu, false jika antrian penuh atau sudah ditutup
+func (q *Queue) Enqueue(job func(ctx context.Context)) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return false
+	}
+
+	select {
+	case q.jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// tolak pekerjaan baru lalu tunggu antrian habis diproses, berhenti menunggu saat ctx selesai
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,143 +1,104 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/gibranfajar/backend-codetech/cache"
 	"github.com/gibranfajar/backend-codetech/config"
-	"github.com/gibranfajar/backend-codetech/controller"
-	"github.com/gibranfajar/backend-codetech/middlewares"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
+	"github.com/gibranfajar/backend-codetech/mailer"
+	"github.com/gibranfajar/backend-codetech/metrics"
+	"github.com/gibranfajar/backend-codetech/openapi"
+	"github.com/gibranfajar/backend-codetech/routes"
 )
 
 func main() {
 
+	// logger JSON
+	config.InitLogger()
+
+	// tracing OpenTelemetry
+	shutdownTracing := config.InitTracing()
+
 	// koneksi ke database
 	config.ConnectDB()
 
+	// collector prometheus
+	metrics.Register(config.DB)
+
 	// validator
 	config.InitValidator()
 
+	// mailer
+	mailer.InitMailer()
+
+	// key untuk sign dan verifikasi JWT
+	config.InitJWT()
+
+	// key enkripsi secret 2FA
+	config.InitEncryptionKey()
+
+	// cache response publik
+	cache.InitCache()
+
+	// store rate limit (memory / redis)
+	config.ConnectRedis()
+	routes.InitRateLimitStore()
+
 	// inisialisasi router
-	router := gin.Default()
-
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173", "https://codetech.crx.my.id"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
-	}))
-
-	// izinkan cors
-	router.Use(cors.New(cors.Config{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
-	}))
-
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "API CONNECTED SUCCESSFULLY✅",
-		})
-	})
-
-	// routers
-	router.POST("/api/login", controller.Login)
-	router.POST("/api/create-user", controller.CreateUser)
-
-	user := router.Group("/api")
-	user.GET("/pages", controller.GetAllPages)
-	user.GET("/abouts", controller.GetAllAbout)
-	user.GET("/services", controller.GetAllServices)
-	user.GET("/portfolios", controller.GetAllPortfolio)
-	user.GET("/products", controller.GetAllProduct)
-	user.GET("/contacts", controller.GetAllContact)
-	user.GET("/users", controller.GetUserNotAdmin)
-	user.GET("/category-articles", controller.GetAllCategoryArticle)
-	user.GET("/articles", controller.GetAllArticle)
-	user.GET("/category-faqs", controller.GetAllCategoryFaq)
-	user.GET("/faqs", controller.GetAllFaq)
-	// update counter views artikel
-	user.GET("/articles/:slug/views", controller.IncrementArticleViews)
-
-	// router untuk admin
-	protected := router.Group("/api/admin")
-	protected.Use(middlewares.AuthMiddleware())
-	{
-		// route pages
-		protected.GET("/pages", controller.GetAllPages)
-		protected.POST("/pages", controller.CreatePage)
-		protected.PUT("/pages/:id", controller.UpdatePage)
-		protected.DELETE("/pages/:id", controller.DeletePage)
-
-		// route about
-		protected.GET("/abouts", controller.GetAllAbout)
-		protected.POST("/abouts", controller.CreateAbout)
-		protected.PUT("/abouts/:id", controller.UpdateAbout)
-		protected.DELETE("/abouts/:id", controller.DeleteAbout)
-
-		// route services
-		protected.GET("/services", controller.GetAllServices)
-		protected.POST("/services", controller.CreateService)
-		protected.PUT("/services/:id", controller.UpdateService)
-		protected.DELETE("/services/:id", controller.DeleteService)
-
-		// route portfolios
-		protected.GET("/portfolios", controller.GetAllPortfolio)
-		protected.POST("/portfolios", controller.CreatePortfolio)
-		protected.PUT("/portfolios/:id", controller.UpdatePortfolio)
-		protected.DELETE("/portfolios/:id", controller.DeletePortfolio)
-
-		// route products
-		protected.GET("/products", controller.GetAllProduct)
-		protected.POST("/products", controller.CreateProduct)
-		protected.PUT("/products/:id", controller.UpdateProduct)
-		protected.DELETE("/products/:id", controller.DeleteProduct)
-
-		// route contacts
-		protected.GET("/contacts", controller.GetAllContact)
-		protected.POST("/contacts", controller.CreateContact)
-		protected.PUT("/contacts/:id", controller.UpdateContact)
-		protected.DELETE("/contacts/:id", controller.DeleteContact)
-
-		// route users
-		protected.GET("/users", controller.GetAllUser)
-		protected.POST("/users", controller.CreateUser)
-		protected.PUT("/users/:id", controller.UpdateUser)
-		protected.DELETE("/users/:id", controller.DeleteUser)
-		// get user by is login
-		protected.GET("/users/me", controller.GetUser)
-
-		// route category faq
-		protected.GET("/category-faqs", controller.GetAllCategoryFaq)
-		protected.POST("/category-faqs", controller.CreateCategoryFaq)
-		protected.PUT("/category-faqs/:id", controller.UpdateCategoryFaq)
-		protected.DELETE("/category-faqs/:id", controller.DeleteCategoryFaq)
-
-		// route faq
-		protected.GET("/faqs", controller.GetAllFaq)
-		protected.POST("/faqs", controller.CreateFaq)
-		protected.PUT("/faqs/:id", controller.UpdateFaq)
-		protected.DELETE("/faqs/:id", controller.DeleteFaq)
-
-		// route category articles
-		protected.GET("/category-articles", controller.GetAllCategoryArticle)
-		protected.POST("/category-articles", controller.CreateCategoryArticle)
-		protected.PUT("/category-articles/:id", controller.UpdateCategoryArticle)
-		protected.DELETE("/category-articles/:id", controller.DeleteCategoryArticle)
-
-		// route articles
-		protected.GET("/articles", controller.GetAllArticle)
-		protected.POST("/articles", controller.CreateArticle)
-		protected.PUT("/articles/:id", controller.UpdateArticle)
-		protected.DELETE("/articles/:id", controller.DeleteArticle)
+	router := routes.SetupRouter()
+
+	// setiap route wajib punya dokumentasi di openapi.Operations, dipaksa oleh test di package openapi
+	if missing := openapi.MissingRoutes(router.Routes()); len(missing) > 0 {
+		slog.Warn("openapi: route tanpa dokumentasi", "routes", missing)
 	}
 
-	// route static untuk menampilkan gambar
-	router.Static("/uploads", "uploads")
+	server := &http.Server{
+		Addr:              config.GetEnv("APP_ADDR", ":8080"),
+		Handler:           router,
+		ReadHeaderTimeout: config.GetEnvDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:       config.GetEnvDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+		WriteTimeout:      config.GetEnvDuration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       config.GetEnvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+	}
 
-	router.Run(":8080")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("Server listening", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			config.Fatal("Server error", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	slog.Info("Shutting down, menunggu request yang sedang berjalan")
+
+	// selesaikan request yang masih berjalan sebelum menutup database
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.GetEnvDuration("SHUTDOWN_TIMEOUT", 20*time.Second))
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Shutdown tidak selesai", "error", err)
+	}
 
+	// email yang masih di antrian dikirim dulu karena butuh database
+	if err := mailer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Antrian email tidak selesai", "error", err)
+	}
+
+	config.CloseDB()
+	config.CloseRedis()
+
+	// kirim span yang masih tertahan di batcher
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Gagal flush trace", "error", err)
+	}
+	slog.Info("Server stopped")
 }
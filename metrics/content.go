@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gauge bisnis dari database, hasil query di-cache agar scrape tidak membebani db
+type contentCollector struct {
+	db       *sql.DB
+	ttl      time.Duration
+	articles *prometheus.Desc
+	views    *prometheus.Desc
+
+	mu        sync.Mutex
+	fetchedAt time.Time
+	counts    [2]float64
+}
+
+func newContentCollector(db *sql.DB) *contentCollector {
+	return &contentCollector{
+		db:       db,
+		ttl:      30 * time.Second,
+		articles: prometheus.NewDesc(namespace+"_articles_published", "Published articles.", nil, nil),
+		views:    prometheus.NewDesc(namespace+"_article_views", "Total views across all articles.", nil, nil),
+	}
+}
+
+func (c *contentCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.articles
+	ch <- c.views
+}
+
+func (c *contentCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.fetchedAt) > c.ttl {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// artikel belum punya status draft, semua artikel dianggap terbit
+		var articles, views int64
+		err := c.db.QueryRowContext(ctx, "SELECT COUNT(*), COALESCE(SUM(views), 0) FROM articles").Scan(&articles, &views)
+		if err != nil {
+			slog.Error("Failed to collect content metrics", "error", err)
+		} else {
+			c.counts = [2]float64{float64(articles), float64(views)}
+			c.fetchedAt = time.Now()
+		}
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.articles, prometheus.GaugeValue, c.counts[0])
+	ch <- prometheus.MustNewConstMetric(c.views, prometheus.GaugeValue, c.counts[1])
+}
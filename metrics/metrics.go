@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"database/sql"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "codetech"
+
+var (
+	// durasi request per route template (bukan path asli) agar label tidak meledak
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	UploadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "uploads_total",
+		Help:      "Uploaded files by resource.",
+	}, []string{"resource"})
+
+	UploadBytesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes_total",
+		Help:      "Uploaded bytes by resource.",
+	}, []string{"resource"})
+
+	// result: success, challenge (menunggu 2FA), failure, locked
+	LoginAttemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Login attempts by result.",
+	}, []string{"result"})
+)
+
+// daftarkan semua collector, dipanggil sekali saat startup setelah database terhubung
+func Register(db *sql.DB) {
+	prometheus.MustRegister(
+		HTTPRequestDuration,
+		UploadsTotal,
+		UploadBytesTotal,
+		LoginAttemptsTotal,
+		collectors.NewDBStatsCollector(db, "codetech"),
+		newContentCollector(db),
+	)
+}
+
+func ObserveRequest(method, route string, status int, seconds float64) {
+	HTTPRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(seconds)
+}
+
+func ObserveUpload(resource string, size int64) {
+	UploadsTotal.WithLabelValues(resource).Inc()
+	UploadBytesTotal.WithLabelValues(resource).Add(float64(size))
+}
+
+func RecordLogin(result string) {
+	LoginAttemptsTotal.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,105 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// catat setiap create, update dan delete ke audit_logs
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var action string
+		switch c.Request.Method {
+		case http.MethodPost:
+			action = "create"
+		case http.MethodPut, http.MethodPatch:
+			action = "update"
+		case http.MethodDelete:
+			action = "delete"
+		default:
+			c.Next()
+			return
+		}
+
+		resource, table := auditResource(c.FullPath())
+
+		// snapshot data sebelum diubah
+		var resourceId *int
+		if id, err := strconv.Atoi(c.Param("id")); err == nil {
+			resourceId = &id
+		} else if isSelfRoute(c.FullPath()) {
+			// route /users/me memakai id user yang sedang login
+			if id := c.GetInt("user_id"); id != 0 {
+				resourceId = &id
+			}
+		}
+
+		// snapshot tetap diambil walau client memutus koneksi setelah data berubah
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		var before []byte
+		if resourceId != nil && table != "" {
+			before = utils.AuditSnapshot(ctx, table, *resourceId)
+		}
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		// handler bisa mengganti nama action, contoh unlock
+		if custom := c.GetString("audit_action"); custom != "" {
+			action = custom
+		}
+
+		// handler create menyimpan id baru di context
+		if resourceId == nil {
+			if id, ok := c.Get("audit_resource_id"); ok {
+				if v, ok := id.(int); ok {
+					resourceId = &v
+				}
+			}
+		}
+
+		var after []byte
+		if action != "delete" && resourceId != nil && table != "" {
+			after = utils.AuditSnapshot(ctx, table, *resourceId)
+		}
+
+		utils.RecordAudit(c, action, resource, resourceId, before, after)
+	}
+}
+
+// route milik user yang sedang login, contoh /api/admin/users/me/password
+func isSelfRoute(fullPath string) bool {
+	for _, segment := range strings.Split(strings.Trim(fullPath, "/"), "/") {
+		if segment == "me" {
+			return true
+		}
+	}
+	return false
+}
+
+// cari nama resource dari route, contoh /api/admin/pages/:id -> pages
+// segmen paling akhir dipakai agar sub-resource (users/:id/author-profile) tercatat sesuai tabelnya
+func auditResource(fullPath string) (string, string) {
+	segments := strings.Split(strings.Trim(fullPath, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if table, ok := utils.AuditTables[segments[i]]; ok {
+			return table, table
+		}
+	}
+
+	for _, segment := range segments {
+		if segment != "api" && segment != "admin" && !strings.HasPrefix(segment, ":") {
+			return segment, ""
+		}
+	}
+	return fullPath, ""
+}
@@ -4,14 +4,21 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/lib/pq"
 )
 
-var jwtSecret = []byte("secret-codetech")
-
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// api key bisa dikirim lewat X-API-Key atau Authorization: Bearer ctk_...
+		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+			authenticateApiKey(c, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -20,14 +27,12 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+		if strings.HasPrefix(tokenString, utils.ApiKeyPrefix) {
+			authenticateApiKey(c, tokenString)
+			return
+		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// validasi metode signing
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return jwtSecret, nil
-		})
+		token, err := utils.ParseToken(tokenString)
 
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
@@ -42,8 +47,83 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Simpan user_id di context
-		c.Set("user_id", int(claims["user_id"].(float64)))
+		// challenge token 2FA tidak boleh dipakai untuk akses api
+		userId, ok := claims["user_id"].(float64)
+		if _, hasPurpose := claims["purpose"]; !ok || hasPurpose {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
+			c.Abort()
+			return
+		}
+
+		// role diambil dari database agar perubahan role langsung berlaku,
+		// sesi yang sudah dicabut (ganti password, logout) ditolak
+		sessionId, _ := claims["sid"].(string)
+		var role string
+		var twoFactorEnabled bool
+		err = config.DB.QueryRowContext(c.Request.Context(), `
+			SELECT u.role, tf.enabled_at IS NOT NULL
+			FROM users u
+			JOIN user_sessions s ON s.user_id = u.id AND s.id = $2 AND s.revoked_at IS NULL AND s.expires_at > NOW()
+			LEFT JOIN user_two_factors tf ON tf.user_id = u.id
+			WHERE u.id = $1
+		`, int(userId), sessionId).Scan(&role, &twoFactorEnabled)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		// role yang wajib 2FA hanya boleh akses enrollment sampai 2FA aktif
+		if !twoFactorEnabled && utils.TwoFactorRequired(role) && !strings.Contains(c.FullPath(), "/2fa/") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication enrollment required", "two_factor_setup_required": true})
+			c.Abort()
+			return
+		}
+
+		// Simpan user_id, role dan sesi di context
+		c.Set("user_id", int(userId))
+		c.Set("role", role)
+		c.Set("session_id", sessionId)
 		c.Next()
 	}
 }
+
+// autentikasi dengan api key, akses dibatasi sesuai scope
+func authenticateApiKey(c *gin.Context, key string) {
+	ctx := c.Request.Context()
+	var apiKeyId, userId int
+	var role string
+	var scopes []string
+	err := config.DB.QueryRowContext(ctx, `
+		SELECT k.id, k.created_by, u.role, k.scopes
+		FROM api_keys k
+		JOIN users u ON k.created_by = u.id
+		WHERE k.key_hash = $1 AND k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > NOW())
+	`, utils.HashToken(key)).Scan(&apiKeyId, &userId, &role, pq.Array(&scopes))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		c.Abort()
+		return
+	}
+
+	if !utils.ApiKeyAllows(scopes, c.Request.Method, c.FullPath()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key scope does not allow this request"})
+		c.Abort()
+		return
+	}
+
+	// last_used_at cukup diperbarui maksimal sekali per menit
+	_, err = config.DB.ExecContext(ctx, `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`, apiKeyId)
+	if err != nil {
+		utils.Logger(c).Error("Failed to update api key last_used_at", "error", err)
+	}
+
+	// request dengan api key berjalan atas nama pembuat key
+	c.Set("user_id", userId)
+	c.Set("role", role)
+	c.Set("api_key_id", apiKeyId)
+	c.Next()
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"regexp"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// /api/admin, /api/v1/admin, /api/v2/admin, ...
+var adminPath = regexp.MustCompile(`^/api(/v\d+)?/admin(/|$)`)
+
+// satu middleware CORS global (preflight OPTIONS tidak pernah masuk group),
+// kebijakan dipilih dari path: admin atau publik
+func CORSMiddleware(public, admin cors.Config) gin.HandlerFunc {
+	publicCors := cors.New(public)
+	adminCors := cors.New(admin)
+
+	return func(c *gin.Context) {
+		if adminPath.MatchString(c.Request.URL.Path) {
+			adminCors(c)
+			return
+		}
+		publicCors(c)
+	}
+}
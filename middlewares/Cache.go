@@ -0,0 +1,124 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/cache"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// writer yang menahan body response agar bisa disimpan ke cache dan dihitung ETag-nya
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int)                 { w.status = code }
+func (w *bufferedWriter) WriteHeaderNow()                      {}
+func (w *bufferedWriter) Write(data []byte) (int, error)       { return w.body.Write(data) }
+func (w *bufferedWriter) WriteString(data string) (int, error) { return w.body.WriteString(data) }
+func (w *bufferedWriter) Status() int                          { return w.status }
+func (w *bufferedWriter) Size() int                            { return w.body.Len() }
+func (w *bufferedWriter) Written() bool                        { return w.body.Len() > 0 }
+
+// cache response GET publik per URL dalam group, plus Cache-Control, ETag dan Last-Modified.
+// group dihapus dari cache oleh handler admin lewat cache.Invalidate.
+// lastModified mengembalikan updated_at terbaru konten group, boleh nil jika tidak ada
+func CacheMiddleware(store *cache.Cache, group, cacheControl string, lastModified func(ctx context.Context) (time.Time, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || store == nil {
+			c.Next()
+			return
+		}
+
+		key := group + ":" + c.Request.URL.RequestURI()
+		if entry, ok := store.Get(key); ok {
+			c.Header("X-Cache", "HIT")
+			serveCached(c, entry, cacheControl)
+			c.Abort()
+			return
+		}
+
+		// konten yang berubah selama handler berjalan tidak boleh masuk cache
+		version := store.Version(group)
+
+		// dibaca sebelum handler agar tidak lebih baru dari isi response
+		var modified time.Time
+		if lastModified != nil {
+			var err error
+			if modified, err = lastModified(c.Request.Context()); err != nil {
+				utils.Logger(c).Warn("Failed to read cache last modified", "group", group, "error", err)
+			}
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original, status: http.StatusOK}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		if writer.status != http.StatusOK {
+			c.Data(writer.status, original.Header().Get("Content-Type"), writer.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(writer.body.Bytes())
+		entry := cache.Entry{
+			Status:       writer.status,
+			ContentType:  original.Header().Get("Content-Type"),
+			Body:         writer.body.Bytes(),
+			ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+			LastModified: modified.UTC().Truncate(time.Second),
+		}
+		if store.Version(group) == version {
+			store.Set(key, entry)
+		}
+
+		c.Header("X-Cache", "MISS")
+		serveCached(c, entry, cacheControl)
+	}
+}
+
+func serveCached(c *gin.Context, entry cache.Entry, cacheControl string) {
+	c.Header("Cache-Control", cacheControl)
+	c.Header("ETag", entry.ETag)
+	if !entry.LastModified.IsZero() {
+		c.Header("Last-Modified", entry.LastModified.Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, entry.ETag, entry.LastModified) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Data(entry.Status, entry.ContentType, entry.Body)
+}
+
+// conditional GET: If-None-Match diutamakan, baru If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		return !lastModified.After(since)
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tandai route usang dengan header Deprecation (RFC 9745) dan Sunset (RFC 8594),
+// Link menunjuk ke path pengganti dengan prefix "to"
+func DeprecationMiddleware(deprecatedAt, sunset time.Time, from, to string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if to != "" {
+			successor := to + strings.TrimPrefix(c.Request.URL.Path, from)
+			c.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// access log JSON per request, semua 5xx dicatat level error beserta errornya
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"ip", c.ClientIP(),
+			"size", c.Writer.Size(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.Errors())
+		}
+
+		logger := utils.Logger(c)
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Error("request failed", attrs...)
+		case status >= http.StatusBadRequest:
+			logger.Warn("request rejected", attrs...)
+		default:
+			logger.Info("request", attrs...)
+		}
+	}
+}
+
+// panic di handler dicatat lengkap di log, client hanya menerima 500
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		utils.Logger(c).Error("panic recovered", slog.Any("panic", recovered), "stack", string(debug.Stack()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	})
+}
@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// catat latency per route template, route tidak dikenal digabung jadi "unmatched"
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start).Seconds())
+	}
+}
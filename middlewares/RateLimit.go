@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/ratelimit"
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// batasi request per IP, atau per API key jika request sudah diautentikasi dengan api key.
+// untuk route admin pasang setelah AuthMiddleware agar api_key_id sudah tersedia
+func RateLimitMiddleware(store ratelimit.Store, policy ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if apiKeyId, ok := c.Get("api_key_id"); ok {
+			key = fmt.Sprintf("key:%v", apiKeyId)
+		}
+
+		result, err := store.Take(c.Request.Context(), policy.Name+":"+key, policy)
+		if err != nil {
+			// store bermasalah jangan sampai mematikan api
+			utils.Logger(c).Error("Rate limit store error", "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(policy.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Burst, int(math.Round(float64(policy.Burst)/policy.Rate))))
+
+		if !result.Allowed {
+			retryAfter := max(ceilSeconds(result.RetryAfter), 1)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "Too many requests, please try again later",
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
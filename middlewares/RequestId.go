@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"log/slog"
+	"regexp"
+
+	"github.com/gibranfajar/backend-codetech/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIdHeader = "X-Request-ID"
+
+// request id dari client hanya dipakai jika formatnya aman untuk log
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// pakai X-Request-ID dari client atau buat baru, lalu kirim balik di response
+func RequestIdMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(requestIdHeader)
+		if !validRequestId.MatchString(requestId) {
+			requestId = uuid.New().String()
+		}
+
+		c.Set("request_id", requestId)
+		c.Set(utils.LoggerKey, slog.Default().With("request_id", requestId))
+		c.Header(requestIdHeader, requestId)
+
+		c.Next()
+	}
+}
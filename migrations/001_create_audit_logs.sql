@@ -0,0 +1,46 @@
+-- audit log untuk setiap perubahan data oleh admin (append-only)
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id            BIGSERIAL PRIMARY KEY,
+    actor_id      INTEGER,
+    action        VARCHAR(50)  NOT NULL,
+    resource_type VARCHAR(100) NOT NULL,
+    resource_id   INTEGER,
+    before        JSONB,
+    after         JSONB,
+    ip            VARCHAR(64)  NOT NULL DEFAULT '',
+    user_agent    TEXT         NOT NULL DEFAULT '',
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs (resource_type, resource_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
+
+-- tolak semua UPDATE / DELETE / TRUNCATE, termasuk dari owner tabel
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_update ON audit_logs;
+CREATE TRIGGER trg_audit_logs_no_update
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_append_only();
+
+DROP TRIGGER IF EXISTS trg_audit_logs_no_truncate ON audit_logs;
+CREATE TRIGGER trg_audit_logs_no_truncate
+    BEFORE TRUNCATE ON audit_logs
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_logs_append_only();
+
+-- role aplikasi hanya boleh INSERT dan SELECT
+REVOKE UPDATE, DELETE, TRUNCATE ON audit_logs FROM PUBLIC;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'codetech_app') THEN
+        REVOKE UPDATE, DELETE, TRUNCATE ON audit_logs FROM codetech_app;
+        GRANT INSERT, SELECT ON audit_logs TO codetech_app;
+        GRANT USAGE ON SEQUENCE audit_logs_id_seq TO codetech_app;
+    END IF;
+END
+$$;
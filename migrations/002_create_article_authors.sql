@@ -0,0 +1,9 @@
+-- co-author artikel, penulis utama tetap di articles.user_id
+CREATE TABLE IF NOT EXISTS article_authors (
+    article_id INTEGER   NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (article_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_authors_user ON article_authors (user_id);
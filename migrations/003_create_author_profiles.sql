@@ -0,0 +1,21 @@
+-- profil publik penulis (tanpa email dan role)
+CREATE TABLE IF NOT EXISTS author_profiles (
+    user_id    INTEGER      PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    slug       VARCHAR(255) NOT NULL UNIQUE,
+    bio        TEXT         NOT NULL DEFAULT '',
+    avatar     VARCHAR(255) NOT NULL DEFAULT '',
+    website    VARCHAR(255) NOT NULL DEFAULT '',
+    twitter    VARCHAR(255) NOT NULL DEFAULT '',
+    github     VARCHAR(255) NOT NULL DEFAULT '',
+    linkedin   VARCHAR(255) NOT NULL DEFAULT '',
+    instagram  VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- buat profil untuk user yang sudah ada, slug dari nama + id agar unik
+INSERT INTO author_profiles (user_id, slug)
+SELECT u.id,
+       TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(u.name), '[^a-z0-9]+', '-', 'g')) || '-' || u.id
+FROM users u
+ON CONFLICT DO NOTHING;
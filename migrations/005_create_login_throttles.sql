@@ -0,0 +1,7 @@
+-- hitungan gagal login per ip dan per akun (email)
+CREATE TABLE IF NOT EXISTS login_throttles (
+    throttle_key   VARCHAR(320) PRIMARY KEY,
+    failures       INTEGER      NOT NULL DEFAULT 0,
+    locked_until   TIMESTAMP,
+    last_failed_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
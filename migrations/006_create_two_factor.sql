@@ -0,0 +1,19 @@
+-- TOTP 2FA, enabled_at NULL berarti enrollment belum diverifikasi
+CREATE TABLE IF NOT EXISTS user_two_factors (
+    user_id    INTEGER     PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret     VARCHAR(64) NOT NULL,
+    last_step  BIGINT      NOT NULL DEFAULT 0,
+    enabled_at TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+-- recovery code sekali pakai, yang disimpan hanya hash sha256
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    INTEGER   NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  CHAR(64)  NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recovery_codes_user ON user_recovery_codes (user_id);
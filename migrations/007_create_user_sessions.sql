@@ -0,0 +1,12 @@
+-- sesi login, setiap JWT membawa sid agar sesi bisa dicabut
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id         VARCHAR(36)  PRIMARY KEY,
+    user_id    INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    ip         VARCHAR(64)  NOT NULL DEFAULT '',
+    user_agent TEXT         NOT NULL DEFAULT '',
+    expires_at TIMESTAMP    NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user ON user_sessions (user_id);
@@ -0,0 +1,9 @@
+-- hash password lama untuk mencegah pemakaian ulang
+CREATE TABLE IF NOT EXISTS password_histories (
+    id            BIGSERIAL    PRIMARY KEY,
+    user_id       INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_histories_user ON password_histories (user_id, created_at DESC);
@@ -0,0 +1,13 @@
+-- api key untuk client mesin (build pipeline, static site generator)
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           SERIAL       PRIMARY KEY,
+    name         VARCHAR(255) NOT NULL,
+    prefix       VARCHAR(16)  NOT NULL,
+    key_hash     CHAR(64)     NOT NULL UNIQUE,
+    scopes       TEXT[]       NOT NULL DEFAULT '{}',
+    created_by   INTEGER      NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at   TIMESTAMP,
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- format isi artikel (markdown, html, blocks) dan hasil render HTML yang sudah disanitasi.
+-- content_html kosong untuk artikel lama, dirender saat dibaca sampai artikel disimpan ulang
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS content_format VARCHAR(16) NOT NULL DEFAULT 'html',
+    ADD COLUMN IF NOT EXISTS content_html   TEXT        NOT NULL DEFAULT '';
+
+-- gambar dari folder uploads yang dipakai di isi artikel
+CREATE TABLE IF NOT EXISTS article_media (
+    article_id INTEGER      NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    path       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (article_id, path)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_media_path ON article_media (path);
@@ -0,0 +1,8 @@
+-- field yang dihitung saat artikel disimpan agar list artikel tidak perlu memuat isi lengkap.
+-- excerpt_manual menandai excerpt yang diisi penulis sehingga tidak ditimpa excerpt otomatis
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS excerpt        TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS excerpt_manual BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS word_count     INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reading_time   INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS toc            JSONB   NOT NULL DEFAULT '[]';
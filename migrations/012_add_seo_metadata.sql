@@ -0,0 +1,21 @@
+-- metadata SEO yang bisa diisi admin, kolom kosong diganti default dari title, excerpt dan gambar
+ALTER TABLE pages
+    ADD COLUMN IF NOT EXISTS meta_title       VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url    VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image         VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS robots           VARCHAR(64)  NOT NULL DEFAULT '';
+
+ALTER TABLE articles
+    ADD COLUMN IF NOT EXISTS meta_title       VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url    VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image         VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS robots           VARCHAR(64)  NOT NULL DEFAULT '';
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS meta_title       VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description TEXT         NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url    VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image         VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS robots           VARCHAR(64)  NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- gambar yang diupload lewat endpoint gambar artikel, hanya gambar ini yang boleh dipakai
+-- di isi artikel dan boleh dihapus saat tidak lagi dipakai (file profil, banner, icon dll tidak ikut)
+CREATE TABLE IF NOT EXISTS article_uploads (
+    path        VARCHAR(255) PRIMARY KEY,
+    uploaded_by INTEGER      REFERENCES users (id) ON DELETE SET NULL,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+-- secret TOTP disimpan terenkripsi (enc:v1:<base64>), lebih panjang dari secret base32 asli
+ALTER TABLE user_two_factors ALTER COLUMN secret TYPE TEXT;
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type ApiKey struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  int        `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ApiKeyRequest struct {
+	Name      string   `form:"name" validate:"required,min=2"`
+	Scopes    []string `form:"scopes" validate:"required,min=1"`                    // contoh: read, articles:write, *
+	ExpiresAt string   `form:"expires_at" validate:"omitempty,datetime=2006-01-02"` // kosong = tidak kedaluwarsa
+}
@@ -1,36 +1,64 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/content"
+)
 
 type Article struct {
-	Id          int       `json:"id"`
-	Title       string    `json:"title"`
-	Slug        string    `json:"slug"`
-	UserId      int       `json:"user_id"`
-	CategoryId  int       `json:"category_id"`
-	Description string    `json:"description"`
-	Thumbnail   string    `json:"thumbnail"`
-	Views       int       `json:"views"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id            int       `json:"id"`
+	Title         string    `json:"title"`
+	Slug          string    `json:"slug"`
+	UserId        int       `json:"user_id"`
+	CategoryId    int       `json:"category_id"`
+	Description   string    `json:"description"`
+	ContentFormat string    `json:"content_format"`
+	ContentHTML   string    `json:"content_html"`
+	Excerpt       string    `json:"excerpt"`
+	ExcerptManual bool      `json:"excerpt_manual"`
+	Seo           Seo       `json:"seo"`
+	Thumbnail     string    `json:"thumbnail"`
+	Views         int       `json:"views"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type ArticleAuthor struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Profile string `json:"profile"`
 }
 
 type ResponseArticle struct {
-	Id          int       `json:"id"`
-	Title       string    `json:"title"`
-	Slug        string    `json:"slug"`
-	User        string    `json:"user"`
-	Category    string    `json:"category"`
-	Description string    `json:"description"`
-	Thumbnail   string    `json:"thumbnail"`
-	Views       int       `json:"views"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	Id            int               `json:"id"`
+	Title         string            `json:"title"`
+	Slug          string            `json:"slug"`
+	Author        ArticleAuthor     `json:"author"`
+	CoAuthors     []ArticleAuthor   `json:"co_authors"`
+	Category      string            `json:"category"`
+	Description   string            `json:"description,omitempty"` // isi mentah sesuai content_format, hanya di detail
+	ContentFormat string            `json:"content_format"`
+	ContentHTML   string            `json:"content_html,omitempty"` // hasil render yang sudah disanitasi, hanya di detail
+	Toc           []content.Heading `json:"toc,omitempty"`
+	Excerpt       string            `json:"excerpt"`
+	WordCount     int               `json:"word_count"`
+	ReadingTime   int               `json:"reading_time"` // menit
+	Seo           Seo               `json:"seo"`
+	Thumbnail     string            `json:"thumbnail"`
+	Views         int               `json:"views"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
 }
 
 type ArticleRequest struct {
-	Title       string `form:"title" validate:"required"`
-	Description string `form:"description" validate:"required"`
-	CategoryId  int    `form:"category_id" validate:"required"` // Add CategoryId field for article creation
-	UserId      int    `form:"user_id" validate:"required"`     // Add UserId field for article creation
+	Title         string `form:"title" validate:"required"`
+	Description   string `form:"description" validate:"required"`
+	ContentFormat string `form:"content_format" validate:"omitempty,oneof=markdown html blocks"` // default html
+	Excerpt       string `form:"excerpt" validate:"omitempty,max=500"`                           // kosong = dibuat otomatis dari isi
+	CategoryId    int    `form:"category_id" validate:"required"`                                // Add CategoryId field for article creation
+	AuthorId      int    `form:"author_id" validate:"omitempty,min=1"`                           // hanya untuk role privileged, default user yang login
+	CoAuthors     []int  `form:"co_authors"`                                                     // id user co-author (opsional)
+	SeoRequest
 }
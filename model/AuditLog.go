@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLog struct {
+	Id           int             `json:"id"`
+	ActorId      *int            `json:"actor_id"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceId   *int            `json:"resource_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	Ip           string          `json:"ip"`
+	UserAgent    string          `json:"user_agent"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type AuditLogFilter struct {
+	ActorId      int    `form:"actor_id" validate:"omitempty,min=1"`
+	Action       string `form:"action"`
+	ResourceType string `form:"resource_type"`
+	ResourceId   int    `form:"resource_id" validate:"omitempty,min=1"`
+	From         string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To           string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+	Page         int    `form:"page" validate:"omitempty,min=1"`
+	Limit        int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type SocialLinks struct {
+	Website   string `json:"website"`
+	Twitter   string `json:"twitter"`
+	Github    string `json:"github"`
+	Linkedin  string `json:"linkedin"`
+	Instagram string `json:"instagram"`
+}
+
+// profil publik penulis, sengaja tanpa email dan role
+type AuthorProfile struct {
+	Id          int         `json:"id"`
+	Name        string      `json:"name"`
+	Slug        string      `json:"slug"`
+	Bio         string      `json:"bio"`
+	Avatar      string      `json:"avatar"`
+	SocialLinks SocialLinks `json:"social_links"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+type AuthorProfileRequest struct {
+	Slug      string `form:"slug" validate:"omitempty,min=2"`
+	Bio       string `form:"bio" validate:"omitempty,max=2000"`
+	Website   string `form:"website" validate:"omitempty,url"`
+	Twitter   string `form:"twitter" validate:"omitempty,url"`
+	Github    string `form:"github" validate:"omitempty,url"`
+	Linkedin  string `form:"linkedin" validate:"omitempty,url"`
+	Instagram string `form:"instagram" validate:"omitempty,url"`
+}
+
+type PaginationQuery struct {
+	Page  int `form:"page" validate:"omitempty,min=1"`
+	Limit int `form:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -9,6 +9,7 @@ type Pages struct {
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
 	Banner      string    `json:"banner"`
+	Seo         Seo       `json:"seo"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -17,4 +18,5 @@ type PageRequest struct {
 	Title       string `form:"title" validate:"required"`
 	Type        string `form:"type" validate:"required"`
 	Description string `form:"description" validate:"required"`
+	SeoRequest
 }
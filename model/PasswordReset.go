@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type PasswordReset struct {
+	Id        int        `json:"id"`
+	UserId    int        `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `form:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token                string `form:"token" validate:"required"`
+	Password             string `form:"password" validate:"required"`
+	PasswordConfirmation string `form:"password_confirmation" validate:"required,eqfield=Password"`
+}
@@ -0,0 +1,19 @@
+package model
+
+// metadata SEO untuk head tag di situs publik
+type Seo struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	CanonicalUrl    string `json:"canonical_url"`
+	OgImage         string `json:"og_image"`
+	Robots          string `json:"robots"`
+}
+
+// field SEO di form admin, kosong = default dari title, excerpt dan gambar
+type SeoRequest struct {
+	MetaTitle       string `form:"meta_title" validate:"omitempty,max=255"`
+	MetaDescription string `form:"meta_description" validate:"omitempty,max=320"`
+	CanonicalUrl    string `form:"canonical_url" validate:"omitempty,url,max=255"`
+	OgImage         string `form:"og_image" validate:"omitempty,uri,max=255"` // url absolut atau path /uploads/...
+	Robots          string `form:"robots" validate:"omitempty,max=64,robots"` // contoh: noindex, nofollow
+}
@@ -8,6 +8,7 @@ type Service struct {
 	Slug        string    `json:"slug"`
 	Description string    `json:"description"`
 	Icon        string    `json:"icon"`
+	Seo         Seo       `json:"seo"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -15,4 +16,5 @@ type Service struct {
 type ServiceRequest struct {
 	Title       string `form:"title" validate:"required"`       // required field
 	Description string `form:"description" validate:"required"` // required field
+	SeoRequest
 }
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type TwoFactor struct {
+	UserId    int        `json:"user_id"`
+	Secret    string     `json:"-"`
+	LastStep  int64      `json:"-"`
+	EnabledAt *time.Time `json:"enabled_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `form:"code" validate:"required"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `form:"challenge_token" validate:"required"`
+	Code           string `form:"code" validate:"required"` // kode TOTP atau recovery code
+}
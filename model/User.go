@@ -16,8 +16,8 @@ type User struct {
 type UserRequest struct {
 	Name     string `form:"name" validate:"required,min=2"`
 	Email    string `form:"email" validate:"required,email"`
-	Password string `form:"password" validate:"required,min=6"`
-	Role     string `form:"role" validate:"required"`
+	Password string `form:"password" validate:"required"` // aturan lain di utils.ValidatePassword
+	Role     string `form:"role"`
 }
 
 type UserRequestUpdate struct {
@@ -26,6 +26,17 @@ type UserRequestUpdate struct {
 	Role  string `form:"role" validate:"required"`
 }
 
+type UserRequestUpdateMe struct {
+	Name  string `form:"name" validate:"required,min=2"`
+	Email string `form:"email" validate:"required,email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword      string `form:"current_password" validate:"required"`
+	Password             string `form:"password" validate:"required"`
+	PasswordConfirmation string `form:"password_confirmation" validate:"required,eqfield=Password"`
+}
+
 type UserResponse struct {
 	Id        int       `json:"id"`
 	Name      string    `json:"name"`
@@ -35,3 +46,11 @@ type UserResponse struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// data user yang aman ditampilkan ke publik
+type PublicUserResponse struct {
+	Id      int    `json:"id"`
+	Name    string `json:"name"`
+	Slug    string `json:"slug"`
+	Profile string `json:"profile"`
+}
@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+//go:embed swagger.html
+var swaggerIndex []byte
+
+// handler Swagger UI untuk route /api/docs/*filepath
+func DocsHandler() gin.HandlerFunc {
+	fileServer := http.StripPrefix("/api/docs", http.FileServer(http.FS(swaggerFiles.FS)))
+
+	return func(c *gin.Context) {
+		path := strings.TrimPrefix(c.Param("filepath"), "/")
+		if path == "" || path == "index.html" {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", swaggerIndex)
+			return
+		}
+		if path == "swagger-initializer.js" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		fileServer.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,121 @@
+package openapi
+
+import "github.com/gibranfajar/backend-codetech/model"
+
+// body login, controller membaca langsung dari PostForm
+type loginForm struct {
+	Email    string `form:"email" validate:"required,email"`
+	Password string `form:"password" validate:"required"`
+}
+
+type loginResponse struct {
+	Message           string `json:"message"`
+	Token             string `json:"token,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type twoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type recoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type createdApiKey struct {
+	model.ApiKey
+	Key string `json:"key"`
+}
+
+type jwks struct {
+	Keys []map[string]string `json:"keys"`
+}
+
+// dokumentasi seluruh route dengan path tanpa versi, route baru wajib ditambahkan di sini
+var Operations = map[string]Operation{
+	"GET /":                           {Summary: "Health message", Tag: "system", Raw: true},
+	"GET /healthz":                    {Summary: "Liveness probe", Tag: "system", Raw: true},
+	"GET /readyz":                     {Summary: "Readiness probe (database, upload dir)", Tag: "system", Raw: true},
+	"GET /metrics":                    {Summary: "Prometheus metrics", Tag: "system", Raw: true},
+	"GET /.well-known/jwks.json":      {Summary: "Public keys for JWT verification", Tag: "auth", Raw: true, Response: jwks{}},
+	"GET /api/openapi.json":           {Summary: "OpenAPI document", Tag: "system", Raw: true},
+	"GET /sitemap.xml":                {Summary: "XML sitemap, or sitemap index above 50k URLs", Tag: "seo", Raw: true},
+	"GET /sitemaps/:name":             {Summary: "Sitemap part referenced by the sitemap index, e.g. 2.xml", Tag: "seo", Raw: true},
+	"GET /robots.txt":                 {Summary: "robots.txt referencing the sitemap", Tag: "seo", Raw: true},
+	"GET /feed.xml":                   {Summary: "RSS 2.0 feed of the latest articles", Tag: "feeds", Raw: true},
+	"GET /feed.atom":                  {Summary: "Atom feed of the latest articles", Tag: "feeds", Raw: true},
+	"GET /categories/:slug/feed.xml":  {Summary: "RSS 2.0 feed of an article category", Tag: "feeds", Raw: true},
+	"GET /categories/:slug/feed.atom": {Summary: "Atom feed of an article category", Tag: "feeds", Raw: true},
+
+	"POST /api/login":           {Summary: "Login with email and password", Tag: "auth", Request: loginForm{}, Raw: true, Response: loginResponse{}},
+	"POST /api/login/2fa":       {Summary: "Complete login with a two-factor code", Tag: "auth", Request: model.TwoFactorLoginRequest{}, Raw: true, Response: loginResponse{}},
+	"POST /api/create-user":     {Summary: "Register a user", Tag: "users", Request: model.UserRequest{}, Files: []string{"profile"}},
+	"POST /api/password/forgot": {Summary: "Request a password reset email", Tag: "auth", Request: model.ForgotPasswordRequest{}},
+	"POST /api/password/reset":  {Summary: "Reset password with a token", Tag: "auth", Request: model.ResetPasswordRequest{}},
+
+	"GET /api/pages":                  {Summary: "List pages", Tag: "pages", Response: model.Pages{}, List: true},
+	"GET /api/abouts":                 {Summary: "List abouts", Tag: "abouts", Response: model.About{}, List: true},
+	"GET /api/services":               {Summary: "List services", Tag: "services", Response: model.Service{}, List: true},
+	"GET /api/services/:slug":         {Summary: "Get service", Tag: "services", Response: model.Service{}},
+	"GET /api/portfolios":             {Summary: "List portfolios", Tag: "portfolios", Response: model.Portfolio{}, List: true},
+	"GET /api/products":               {Summary: "List products", Tag: "products", Response: model.Product{}, List: true},
+	"GET /api/contacts":               {Summary: "List contacts", Tag: "contacts", Response: model.Contact{}, List: true},
+	"GET /api/users":                  {Summary: "List public users", Tag: "users", Response: model.PublicUserResponse{}, List: true},
+	"GET /api/authors/:slug":          {Summary: "Get author profile", Tag: "authors", Response: model.AuthorProfile{}},
+	"GET /api/authors/:slug/articles": {Summary: "List articles by author", Tag: "authors", Query: model.PaginationQuery{}, Response: model.ResponseArticle{}, List: true, Paged: true},
+	"GET /api/category-articles":      {Summary: "List article categories", Tag: "category-articles", Response: model.CategoryArticle{}, List: true},
+	"GET /api/articles":               {Summary: "List articles", Tag: "articles", Response: model.ResponseArticle{}, List: true},
+	"GET /api/articles/:slug":         {Summary: "Get article", Tag: "articles", Response: model.ResponseArticle{}},
+	"GET /api/category-faqs":          {Summary: "List FAQ categories", Tag: "category-faqs", Response: model.CategoryFaq{}, List: true},
+	"GET /api/faqs":                   {Summary: "List FAQs", Tag: "faqs", Response: model.FaqResponse{}, List: true},
+	"GET /api/articles/:slug/views":   {Summary: "Increment article views (v1)", Tag: "articles"},
+	"POST /api/articles/:slug/views":  {Summary: "Increment article views", Tag: "articles"},
+
+	"POST /api/admin/articles/images":         {Summary: "Upload article body image", Tag: "articles", Files: []string{"image"}},
+	"GET /api/admin/users":                    {Summary: "List users", Tag: "users", Response: model.UserResponse{}, List: true},
+	"POST /api/admin/users":                   {Summary: "Create user", Tag: "users", Request: model.UserRequest{}, Files: []string{"profile"}},
+	"PUT /api/admin/users/:id":                {Summary: "Update user", Tag: "users", Request: model.UserRequestUpdate{}, Files: []string{"profile"}},
+	"DELETE /api/admin/users/:id":             {Summary: "Delete user", Tag: "users"},
+	"POST /api/admin/users/:id/unlock":        {Summary: "Clear login lockout", Tag: "users"},
+	"DELETE /api/admin/users/:id/2fa":         {Summary: "Reset user two-factor", Tag: "users"},
+	"GET /api/admin/users/me":                 {Summary: "Get own profile", Tag: "users", Response: model.UserResponse{}},
+	"PUT /api/admin/users/me":                 {Summary: "Update own profile", Tag: "users", Request: model.UserRequestUpdateMe{}, Files: []string{"profile"}},
+	"POST /api/admin/users/me/password":       {Summary: "Change own password", Tag: "users", Request: model.ChangePasswordRequest{}},
+	"PUT /api/admin/users/:id/author-profile": {Summary: "Update author profile", Tag: "authors", Request: model.AuthorProfileRequest{}, Files: []string{"avatar"}, Response: model.AuthorProfile{}},
+	"POST /api/admin/2fa/setup":               {Summary: "Start two-factor enrollment", Tag: "2fa", Response: twoFactorSetup{}},
+	"POST /api/admin/2fa/verify":              {Summary: "Enable two-factor", Tag: "2fa", Request: model.TwoFactorCodeRequest{}, Response: recoveryCodes{}},
+	"POST /api/admin/2fa/disable":             {Summary: "Disable two-factor", Tag: "2fa", Request: model.TwoFactorCodeRequest{}},
+	"GET /api/admin/api-keys":                 {Summary: "List API keys", Tag: "api-keys", Response: model.ApiKey{}, List: true},
+	"POST /api/admin/api-keys":                {Summary: "Create API key", Tag: "api-keys", Request: model.ApiKeyRequest{}, Response: createdApiKey{}},
+	"DELETE /api/admin/api-keys/:id":          {Summary: "Revoke API key", Tag: "api-keys"},
+	"GET /api/admin/audit-logs":               {Summary: "List audit logs", Tag: "audit-logs", Query: model.AuditLogFilter{}, Response: model.AuditLog{}, List: true, Paged: true},
+}
+
+func init() {
+	crud("pages", model.Pages{}, model.PageRequest{}, "banner")
+	crud("abouts", model.About{}, model.AboutRequest{}, "image")
+	crud("services", model.Service{}, model.ServiceRequest{}, "icon")
+	crud("portfolios", model.Portfolio{}, model.PortfolioRequest{}, "image")
+	crud("products", model.Product{}, model.ProductRequest{}, "icon")
+	crud("contacts", model.Contact{}, model.ContactRequest{}, "")
+	crud("category-faqs", model.CategoryFaq{}, model.CategoryFaqRequest{}, "icon")
+	crud("faqs", model.FaqResponse{}, model.FaqRequest{}, "")
+	crud("category-articles", model.CategoryArticle{}, model.CategoryArticleRequest{}, "")
+	crud("articles", model.ResponseArticle{}, model.ArticleRequest{}, "thumbnail")
+}
+
+// route CRUD admin standar: list, create, update, delete
+func crud(resource string, response, request interface{}, file string) {
+	files := []string{}
+	if file != "" {
+		files = append(files, file)
+	}
+	base := "/api/admin/" + resource
+
+	Operations["GET "+base] = Operation{Summary: "List " + resource, Tag: resource, Response: response, List: true}
+	Operations["POST "+base] = Operation{Summary: "Create " + resource, Tag: resource, Request: request, Files: files}
+	Operations["PUT "+base+"/:id"] = Operation{Summary: "Update " + resource, Tag: resource, Request: request, Files: files}
+	Operations["DELETE "+base+"/:id"] = Operation{Summary: "Delete " + resource, Tag: resource}
+}
@@ -0,0 +1,208 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	rawJSONType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema untuk body form (multipart/form-data) dari tag form dan validate
+func formSchema(model interface{}, files []string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+
+	if model != nil {
+		for _, field := range formFields(reflect.TypeOf(model)) {
+			name := strings.Split(field.Tag.Get("form"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+
+			schema := typeSchema(field.Type, nil)
+			if applyValidateTag(schema, field.Tag.Get("validate"), field.Type) {
+				required = append(required, name)
+			}
+			properties[name] = schema
+		}
+	}
+
+	for _, file := range files {
+		properties[file] = map[string]interface{}{"type": "string", "format": "binary"}
+	}
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// field form termasuk field dari struct embedded (contoh SeoRequest)
+func formFields(t reflect.Type) []reflect.StructField {
+	fields := []reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, formFields(field.Type)...)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// parameter query string dari tag form
+func queryParameters(model interface{}) []map[string]interface{} {
+	params := []map[string]interface{}{}
+	if model == nil {
+		return params
+	}
+
+	t := reflect.TypeOf(model)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema := typeSchema(field.Type, nil)
+		params = append(params, map[string]interface{}{
+			"name":     name,
+			"in":       "query",
+			"required": applyValidateTag(schema, field.Tag.Get("validate"), field.Type),
+			"schema":   schema,
+		})
+	}
+	return params
+}
+
+// terjemahkan aturan validator ke constraint schema, return true jika required
+func applyValidateTag(schema map[string]interface{}, tag string, t reflect.Type) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, value, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			return required
+		case "required":
+			required = true
+		case "email":
+			schema["format"] = "email"
+		case "url":
+			schema["format"] = "uri"
+		case "datetime":
+			if value == "2006-01-02" {
+				schema["format"] = "date"
+			} else {
+				schema["format"] = "date-time"
+			}
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			switch t.Kind() {
+			case reflect.String:
+				schema[name+"Length"] = n
+			case reflect.Slice:
+				schema[name+"Items"] = n
+			default:
+				if name == "min" {
+					schema["minimum"] = n
+				} else {
+					schema["maximum"] = n
+				}
+			}
+		}
+	}
+	return required
+}
+
+// schema dari tipe go, struct bernama didaftarkan ke components
+func typeSchema(t reflect.Type, components map[string]interface{}) map[string]interface{} {
+	nullable := false
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+
+	var schema map[string]interface{}
+	switch {
+	case t == timeType:
+		schema = map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawJSONType:
+		schema = map[string]interface{}{"type": "object", "nullable": true}
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			schema = map[string]interface{}{"type": "string"}
+		case reflect.Bool:
+			schema = map[string]interface{}{"type": "boolean"}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			schema = map[string]interface{}{"type": "integer"}
+		case reflect.Float32, reflect.Float64:
+			schema = map[string]interface{}{"type": "number"}
+		case reflect.Slice, reflect.Array:
+			schema = map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), components)}
+		case reflect.Map:
+			schema = map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), components)}
+		case reflect.Struct:
+			schema = structSchema(t, components)
+		default:
+			schema = map[string]interface{}{}
+		}
+	}
+
+	if nullable {
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+	}
+	return schema
+}
+
+// schema response dari tag json, struct bernama dijadikan $ref ke components
+func structSchema(t reflect.Type, components map[string]interface{}) map[string]interface{} {
+	if components == nil || t.Name() == "" {
+		return objectSchema(t, components)
+	}
+
+	if _, ok := components[t.Name()]; !ok {
+		components[t.Name()] = map[string]interface{}{} // placeholder agar tidak rekursi
+		components[t.Name()] = objectSchema(t, components)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+}
+
+func objectSchema(t reflect.Type, components map[string]interface{}) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = typeSchema(field.Type, components)
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func typeSchemaOf(value interface{}, components map[string]interface{}) map[string]interface{} {
+	return typeSchema(reflect.TypeOf(value), components)
+}
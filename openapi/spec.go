@@ -0,0 +1,225 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dokumentasi satu route, key di Operations adalah "METHOD /path" sesuai route gin
+type Operation struct {
+	Summary  string
+	Tag      string
+	Request  interface{} // struct request dengan tag form (multipart/form-data)
+	Files    []string    // field upload file
+	Query    interface{} // struct query string dengan tag form
+	Response interface{} // isi field "data" pada response
+	List     bool        // "data" berupa array
+	Paged    bool        // response punya "meta" pagination
+	Raw      bool        // response tidak dibungkus "data"
+}
+
+var (
+	pathParam     = regexp.MustCompile(`:(\w+)`)
+	versionPrefix = regexp.MustCompile(`^/api/v\d+/`)
+)
+
+// Operations memakai path tanpa versi, /api/v1/pages dan /api/pages sama-sama ke "/api/pages"
+func lookup(route gin.RouteInfo) (Operation, bool) {
+	operation, ok := Operations[route.Method+" "+versionPrefix.ReplaceAllString(route.Path, "/api/")]
+	return operation, ok
+}
+
+// route yang tidak perlu dokumentasi (static file, HEAD otomatis, wildcard)
+func skipRoute(route gin.RouteInfo) bool {
+	return route.Method == http.MethodHead || strings.Contains(route.Path, "*")
+}
+
+// route terdaftar di router tapi belum ada di Operations
+func MissingRoutes(routes gin.RoutesInfo) []string {
+	missing := []string{}
+	for _, route := range routes {
+		if skipRoute(route) {
+			continue
+		}
+		if _, ok := lookup(route); !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// susun dokumen OpenAPI 3 dari route yang terdaftar
+func Build(routes gin.RoutesInfo) map[string]interface{} {
+	components := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"error":  map[string]interface{}{"type": "string"},
+				"errors": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+		},
+	}
+	paths := map[string]map[string]interface{}{}
+	registered := map[string]bool{}
+	for _, route := range routes {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range routes {
+		operation, ok := lookup(route)
+		if skipRoute(route) || !ok {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		result := buildOperation(route, operation, components)
+		// path lama tanpa versi adalah alias v1 yang sudah usang
+		if registered[route.Method+" "+strings.Replace(route.Path, "/api/", "/api/v1/", 1)] {
+			result["deprecated"] = true
+		}
+		paths[path][strings.ToLower(route.Method)] = result
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Codetech API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKey":     map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+}
+
+func buildOperation(route gin.RouteInfo, operation Operation, components map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{
+		"summary":     operation.Summary,
+		"tags":        []string{operation.Tag},
+		"operationId": operationId(route),
+	}
+
+	parameters := []map[string]interface{}{}
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		schema := map[string]interface{}{"type": "string"}
+		if match[1] == "id" {
+			schema["type"] = "integer"
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   schema,
+		})
+	}
+	parameters = append(parameters, queryParameters(operation.Query)...)
+	if len(parameters) > 0 {
+		result["parameters"] = parameters
+	}
+
+	if operation.Request != nil || len(operation.Files) > 0 {
+		contentType := "application/x-www-form-urlencoded"
+		if len(operation.Files) > 0 {
+			contentType = "multipart/form-data"
+		}
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				contentType: map[string]interface{}{"schema": formSchema(operation.Request, operation.Files)},
+			},
+		}
+	}
+
+	if strings.Contains(route.Path, "/admin/") {
+		result["security"] = []map[string][]string{{"bearerAuth": {}}, {"apiKey": {}}}
+	}
+
+	result["responses"] = map[string]interface{}{
+		successStatus(route.Method): map[string]interface{}{
+			"description": "Success",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": responseSchema(operation, components)},
+			},
+		},
+		"default": map[string]interface{}{
+			"description": "Error",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": map[string]interface{}{"$ref": "#/components/schemas/Error"}},
+			},
+		},
+	}
+
+	return result
+}
+
+func responseSchema(operation Operation, components map[string]interface{}) map[string]interface{} {
+	if operation.Raw {
+		if operation.Response == nil {
+			return map[string]interface{}{"type": "object"}
+		}
+		return typeSchemaOf(operation.Response, components)
+	}
+
+	properties := map[string]interface{}{
+		"message": map[string]interface{}{"type": "string"},
+	}
+	if operation.Response != nil {
+		data := typeSchemaOf(operation.Response, components)
+		if operation.List {
+			data = map[string]interface{}{"type": "array", "items": data}
+		}
+		properties["data"] = data
+	}
+	if operation.Paged {
+		properties["meta"] = map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"page":  map[string]interface{}{"type": "integer"},
+				"limit": map[string]interface{}{"type": "integer"},
+				"total": map[string]interface{}{"type": "integer"},
+			},
+		}
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+func successStatus(method string) string {
+	if method == http.MethodPost {
+		return "201"
+	}
+	return "200"
+}
+
+// contoh: PUT /api/admin/pages/:id -> put_api_admin_pages_id
+func operationId(route gin.RouteInfo) string {
+	id := strings.ToLower(route.Method) + "_" + strings.Trim(route.Path, "/")
+	replacer := strings.NewReplacer("/", "_", ":", "", "-", "_", ".", "_")
+	return replacer.Replace(id)
+}
+
+// handler /api/openapi.json, dokumen dibuat sekali dari route router
+func SpecHandler(router *gin.Engine) gin.HandlerFunc {
+	var once sync.Once
+	var document map[string]interface{}
+
+	return func(c *gin.Context) {
+		once.Do(func() {
+			document = Build(router.Routes())
+		})
+		c.JSON(http.StatusOK, document)
+	}
+}
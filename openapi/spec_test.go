@@ -0,0 +1,23 @@
+package openapi_test
+
+import (
+	"testing"
+
+	"github.com/gibranfajar/backend-codetech/cache"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/openapi"
+	"github.com/gibranfajar/backend-codetech/routes"
+	"github.com/gin-gonic/gin"
+)
+
+// setiap route yang terdaftar harus punya dokumentasi di Operations
+func TestAllRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.InitJWT()
+	cache.InitCache()
+
+	router := routes.SetupRouter()
+	if missing := openapi.MissingRoutes(router.Routes()); len(missing) > 0 {
+		t.Fatalf("routes without openapi documentation: %v", missing)
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kebijakan token bucket: Burst token maksimal, terisi ulang Rate token per detik
+type Policy struct {
+	Name  string
+	Rate  float64
+	Burst int
+}
+
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // waktu sampai 1 token tersedia (jika ditolak)
+	Reset      time.Duration // waktu sampai bucket penuh lagi
+}
+
+// penyimpanan bucket, in-memory untuk satu instance atau Redis untuk banyak instance
+type Store interface {
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+}
+
+// format "<jumlah>/<durasi>", contoh 10/1m, 300/1m, 5/1h
+func ParsePolicy(name, value string) (Policy, error) {
+	count, window, ok := strings.Cut(value, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("rate limit %s: invalid format %q", name, value)
+	}
+	limit, err := strconv.Atoi(count)
+	if err != nil || limit <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %s: invalid count %q", name, count)
+	}
+	duration, err := time.ParseDuration(window)
+	if err != nil || duration <= 0 {
+		return Policy{}, fmt.Errorf("rate limit %s: invalid window %q", name, window)
+	}
+	return Policy{Name: name, Rate: float64(limit) / duration.Seconds(), Burst: limit}, nil
+}
+
+// hitung ulang isi bucket, dipakai store in-memory (store Redis memakai rumus yang sama di Lua)
+func take(tokens float64, last, now time.Time, policy Policy) (float64, Result) {
+	tokens = min(float64(policy.Burst), tokens+now.Sub(last).Seconds()*policy.Rate)
+
+	result := Result{}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / policy.Rate)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = seconds((float64(policy.Burst) - tokens) / policy.Rate)
+	return tokens, result
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		rate    float64
+		burst   int
+		wantErr bool
+	}{
+		{"10/1m", 10.0 / 60, 10, false},
+		{"300/1m", 5, 300, false},
+		{"5/1h", 5.0 / 3600, 5, false},
+		{"1/1s", 1, 1, false},
+		{"10", 0, 0, true},
+		{"abc/1m", 0, 0, true},
+		{"0/1m", 0, 0, true},
+		{"-1/1m", 0, 0, true},
+		{"10/abc", 0, 0, true},
+		{"10/0s", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParsePolicy("test", tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParsePolicy(%q) expected error", tt.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParsePolicy(%q): %v", tt.value, err)
+			continue
+		}
+		if policy.Burst != tt.burst || policy.Rate != tt.rate || policy.Name != "test" {
+			t.Errorf("ParsePolicy(%q) = %+v, want rate %v burst %d", tt.value, policy, tt.rate, tt.burst)
+		}
+	}
+}
+
+func TestTake(t *testing.T) {
+	policy := Policy{Name: "test", Rate: 1, Burst: 5}
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name          string
+		tokens        float64
+		elapsed       time.Duration
+		wantAllowed   bool
+		wantRemaining int
+		wantTokens    float64
+		wantRetry     time.Duration
+		wantReset     time.Duration
+	}{
+		{"full bucket", 5, 0, true, 4, 4, 0, time.Second},
+		{"last token", 1, 0, true, 0, 0, 0, 5 * time.Second},
+		{"empty bucket", 0, 0, false, 0, 0, time.Second, 5 * time.Second},
+		{"half refilled", 0, 500 * time.Millisecond, false, 0, 0.5, 500 * time.Millisecond, 4500 * time.Millisecond},
+		{"refilled after wait", 0, 2 * time.Second, true, 1, 1, 0, 4 * time.Second},
+		{"refill capped at burst", 3, time.Hour, true, 4, 4, 0, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, result := take(tt.tokens, now.Add(-tt.elapsed), now, policy)
+			if result.Allowed != tt.wantAllowed || result.Remaining != tt.wantRemaining {
+				t.Errorf("allowed/remaining = %v/%d, want %v/%d", result.Allowed, result.Remaining, tt.wantAllowed, tt.wantRemaining)
+			}
+			if tokens != tt.wantTokens {
+				t.Errorf("tokens = %v, want %v", tokens, tt.wantTokens)
+			}
+			if result.RetryAfter != tt.wantRetry || result.Reset != tt.wantReset {
+				t.Errorf("retry/reset = %v/%v, want %v/%v", result.RetryAfter, result.Reset, tt.wantRetry, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{Name: "test", Rate: 0.001, Burst: 3}
+	ctx := context.Background()
+
+	for i := 0; i < policy.Burst; i++ {
+		result, err := store.Take(ctx, "a", policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d rejected within burst", i+1)
+		}
+	}
+
+	result, _ := store.Take(ctx, "a", policy)
+	if result.Allowed || result.RetryAfter <= 0 {
+		t.Errorf("request over burst = %+v, want rejected with RetryAfter", result)
+	}
+
+	// bucket per key terpisah
+	if result, _ := store.Take(ctx, "b", policy); !result.Allowed {
+		t.Error("other key should have its own bucket")
+	}
+}
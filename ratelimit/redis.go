@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// cukup method Eval, bisa dipenuhi adapter tipis di atas client Redis apa pun
+// (go-redis, rueidis, KeyDB, Dragonfly, dll.)
+type RedisEvaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// token bucket atomik di Redis, waktu memakai jam server Redis agar konsisten antar instance
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = redis.call('TIME')
+now = tonumber(now[1]) + tonumber(now[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(state[1]) or burst
+local last = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + (now - last) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+type RedisStore struct {
+	client RedisEvaler
+	prefix string
+}
+
+func NewRedisStore(client RedisEvaler, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	reply, err := s.client.Eval(ctx, tokenBucketScript, []string{s.prefix + key}, policy.Rate, policy.Burst)
+	if err != nil {
+		return Result{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return Result{}, fmt.Errorf("ratelimit: unexpected redis reply %v", reply)
+	}
+	allowed, _ := values[0].(int64)
+	var tokens float64
+	if _, err := fmt.Sscan(fmt.Sprint(values[1]), &tokens); err != nil {
+		return Result{}, fmt.Errorf("ratelimit: unexpected redis reply %v", reply)
+	}
+
+	result := Result{
+		Allowed:   allowed == 1,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(policy.Burst) - tokens) / policy.Rate),
+	}
+	if !result.Allowed {
+		result.RetryAfter = seconds((1 - tokens) / policy.Rate)
+	}
+	return result, nil
+}
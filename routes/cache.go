@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/cache"
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// updated_at terbaru dari tabel yang isinya tampil di response group, untuk header Last-Modified
+var cacheLastModifiedQueries = map[string]string{
+	"services": "SELECT MAX(updated_at) FROM services",
+	"faqs": `SELECT GREATEST(
+		(SELECT MAX(updated_at) FROM faqs),
+		(SELECT MAX(updated_at) FROM category_faqs))`,
+	"articles": `SELECT GREATEST(
+		(SELECT MAX(updated_at) FROM articles),
+		(SELECT MAX(updated_at) FROM category_articles),
+		(SELECT MAX(updated_at) FROM users),
+		(SELECT MAX(updated_at) FROM author_profiles))`,
+	"sitemap": `SELECT GREATEST(
+		(SELECT MAX(updated_at) FROM pages),
+		(SELECT MAX(updated_at) FROM services),
+		(SELECT MAX(updated_at) FROM articles),
+		(SELECT MAX(updated_at) FROM category_articles))`,
+}
+
+// cache response publik per group konten, dihapus oleh handler admin lewat cache.Invalidate
+func cached(group string) gin.HandlerFunc {
+	cacheControl := config.GetEnv("CACHE_CONTROL", "public, max-age=60, stale-while-revalidate=300")
+
+	var lastModified func(ctx context.Context) (time.Time, error)
+	if query, ok := cacheLastModifiedQueries[group]; ok {
+		lastModified = func(ctx context.Context) (time.Time, error) {
+			var updated sql.NullTime
+			err := config.DB.QueryRowContext(ctx, query).Scan(&updated)
+			return updated.Time, err
+		}
+	}
+	return middlewares.CacheMiddleware(cache.Responses, group, cacheControl, lastModified)
+}
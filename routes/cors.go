@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gin-contrib/cors"
+)
+
+// header yang boleh dikirim dan dibaca frontend lintas origin
+var (
+	corsAllowHeaders  = []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-Request-ID", "traceparent", "tracestate", "If-None-Match", "If-Modified-Since"}
+	corsExposeHeaders = []string{"Content-Length", "Deprecation", "Sunset", "Link", "X-Request-ID", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "ETag", "Last-Modified", "X-Cache"}
+)
+
+// kebijakan CORS dari env CORS_<PREFIX>_ORIGINS, _METHODS, _CREDENTIALS
+func corsConfig(prefix, origins, methods string, credentials bool) cors.Config {
+	result := cors.Config{
+		AllowMethods:  splitList(config.GetEnv("CORS_"+prefix+"_METHODS", methods)),
+		AllowHeaders:  corsAllowHeaders,
+		ExposeHeaders: corsExposeHeaders,
+		MaxAge:        config.GetEnvDuration("CORS_MAX_AGE", 12*time.Hour),
+	}
+
+	allowed := splitList(config.GetEnv("CORS_"+prefix+"_ORIGINS", origins))
+	if len(allowed) == 1 && allowed[0] == "*" {
+		// origin bebas tidak boleh digabung dengan credentials
+		result.AllowAllOrigins = true
+		return result
+	}
+
+	result.AllowOrigins = allowed
+	result.AllowCredentials = config.GetEnvBool("CORS_"+prefix+"_CREDENTIALS", credentials)
+	return result
+}
+
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/middlewares"
+	"github.com/gibranfajar/backend-codetech/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// kebijakan default, bisa diganti lewat env RATE_LIMIT_<NAMA>, contoh RATE_LIMIT_LOGIN=5/1m
+var defaultRateLimits = map[string]string{
+	"login":           "10/1m",
+	"login_2fa":       "10/1m",
+	"password_forgot": "5/15m",
+	"password_reset":  "10/15m",
+	"register":        "5/1h",
+	"views":           "30/1m",
+	"public":          "300/1m",
+	"admin":           "600/1m",
+}
+
+var (
+	// store bucket, dipilih lewat InitRateLimitStore sebelum SetupRouter
+	RateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+
+	rateLimitPolicies map[string]ratelimit.Policy
+	rateLimitOnce     sync.Once
+)
+
+// RATE_LIMIT_STORE=memory (default, per instance) atau redis (dibagi antar instance, butuh REDIS_URL)
+func InitRateLimitStore() {
+	switch store := config.GetEnv("RATE_LIMIT_STORE", "memory"); store {
+	case "memory":
+		RateLimitStore = ratelimit.NewMemoryStore()
+	case "redis":
+		if config.Redis == nil {
+			config.Fatal("RATE_LIMIT_STORE=redis membutuhkan REDIS_URL")
+		}
+		RateLimitStore = ratelimit.NewRedisStore(redisEvaler{config.Redis}, config.GetEnv("RATE_LIMIT_REDIS_PREFIX", "ratelimit:"))
+	default:
+		config.Fatal("RATE_LIMIT_STORE tidak dikenal", "store", store)
+	}
+}
+
+// adapter go-redis ke ratelimit.RedisEvaler
+type redisEvaler struct {
+	client interface {
+		Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
+	}
+}
+
+func (r redisEvaler) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return r.client.Eval(ctx, script, keys, args...).Result()
+}
+
+// middleware rate limit untuk kebijakan tertentu, no-op jika RATE_LIMIT_ENABLED=false
+func rateLimit(name string) gin.HandlerFunc {
+	rateLimitOnce.Do(loadRateLimits)
+
+	policy, ok := rateLimitPolicies[name]
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return middlewares.RateLimitMiddleware(RateLimitStore, policy)
+}
+
+func loadRateLimits() {
+	rateLimitPolicies = map[string]ratelimit.Policy{}
+	if !config.GetEnvBool("RATE_LIMIT_ENABLED", true) {
+		return
+	}
+
+	for name, fallback := range defaultRateLimits {
+		policy, err := ratelimit.ParsePolicy(name, config.GetEnv("RATE_LIMIT_"+strings.ToUpper(name), fallback))
+		if err != nil {
+			config.Fatal("Konfigurasi rate limit tidak valid", "error", err)
+		}
+		rateLimitPolicies[name] = policy
+	}
+}
@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/controller"
+	"github.com/gibranfajar/backend-codetech/middlewares"
+	"github.com/gibranfajar/backend-codetech/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+// susun router beserta seluruh versi API
+func SetupRouter() *gin.Engine {
+	router := gin.New()
+
+	// X-Forwarded-For hanya dipercaya dari proxy di TRUSTED_PROXIES, default tidak ada
+	// sehingga ClientIP (dipakai rate limit) tidak bisa dipalsukan lewat header
+	var trustedProxies []string
+	if proxies := splitList(config.GetEnv("TRUSTED_PROXIES", "")); len(proxies) > 0 {
+		trustedProxies = proxies
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		config.Fatal("TRUSTED_PROXIES tidak valid", "error", err)
+	}
+
+	// request id, span tracing, access log JSON dan recovery panic
+	router.Use(middlewares.RequestIdMiddleware(), middlewares.TracingMiddleware(), middlewares.LoggerMiddleware(), middlewares.RecoveryMiddleware())
+
+	// HSTS, nosniff, Referrer-Policy dan CSP untuk /uploads
+	router.Use(middlewares.SecurityHeadersMiddleware(
+		config.GetEnvInt("HSTS_MAX_AGE", 31536000),
+		config.GetEnv("REFERRER_POLICY", "strict-origin-when-cross-origin"),
+	))
+
+	// satu kebijakan CORS: admin dengan allow-list + credentials, publik boleh semua origin
+	router.Use(middlewares.CORSMiddleware(
+		corsConfig("PUBLIC", "*", "GET,POST,OPTIONS", false),
+		corsConfig("ADMIN", "http://localhost:5173,https://codetech.crx.my.id", "GET,POST,PUT,PATCH,DELETE,OPTIONS", true),
+	))
+
+	// metrik prometheus per route
+	router.Use(middlewares.MetricsMiddleware())
+
+	// deadline untuk seluruh query database dalam satu request
+	router.Use(middlewares.TimeoutMiddleware(config.GetEnvDuration("REQUEST_TIMEOUT", 15*time.Second)))
+
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(200, gin.H{
+			"message": "API CONNECTED SUCCESSFULLY✅",
+		})
+	})
+
+	// probe untuk orkestrasi container
+	router.GET("/healthz", controller.Healthz)
+	router.GET("/readyz", controller.Readyz)
+	router.GET("/metrics", controller.Metrics())
+
+	// public key JWT
+	router.GET("/.well-known/jwks.json", controller.GetJWKS)
+
+	// sitemap dan robots.txt untuk mesin pencari, dibuat ulang saat konten berubah
+	router.GET("/sitemap.xml", rateLimit("public"), cached("sitemap"), controller.Sitemap)
+	router.GET("/sitemaps/:name", rateLimit("public"), cached("sitemap"), controller.SitemapPage)
+	router.GET("/robots.txt", rateLimit("public"), cached("sitemap"), controller.RobotsTxt)
+
+	// feed artikel untuk feed reader dan newsletter, conditional GET lewat ETag / Last-Modified
+	router.GET("/feed.xml", rateLimit("public"), cached("articles"), controller.RSSFeed)
+	router.GET("/feed.atom", rateLimit("public"), cached("articles"), controller.AtomFeed)
+	router.GET("/categories/:slug/feed.xml", rateLimit("public"), cached("articles"), controller.RSSFeed)
+	router.GET("/categories/:slug/feed.atom", rateLimit("public"), cached("articles"), controller.AtomFeed)
+
+	// dokumentasi API
+	router.GET("/api/openapi.json", openapi.SpecHandler(router))
+	router.GET("/api/docs/*filepath", openapi.DocsHandler())
+
+	RegisterV1(router.Group("/api/v1", v1Deprecation()...))
+	RegisterV2(router.Group("/api/v2"))
+
+	// path lama tanpa versi tetap jalan sebagai alias v1
+	RegisterV1(router.Group("/api", middlewares.DeprecationMiddleware(
+		config.GetEnvDate("API_LEGACY_DEPRECATED_AT", "2026-10-19"),
+		config.GetEnvDate("API_LEGACY_SUNSET", "2027-04-30"),
+		"/api", "/api/v1",
+	)))
+
+	// route static untuk menampilkan gambar
+	router.Static("/uploads", "uploads")
+
+	return router
+}
+
+// v1 ditandai usang hanya jika API_V1_DEPRECATED_AT diisi
+func v1Deprecation() []gin.HandlerFunc {
+	deprecatedAt := config.GetEnvDate("API_V1_DEPRECATED_AT", "")
+	if deprecatedAt.IsZero() {
+		return nil
+	}
+	return []gin.HandlerFunc{middlewares.DeprecationMiddleware(deprecatedAt, config.GetEnvDate("API_V1_SUNSET", ""), "/api/v1", "/api/v2")}
+}
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"github.com/gibranfajar/backend-codetech/controller"
+	"github.com/gibranfajar/backend-codetech/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// daftarkan seluruh route API v1 pada group yang diberikan (/api/v1 atau alias /api)
+func RegisterV1(rg *gin.RouterGroup) {
+	registerAuthRoutes(rg)
+	registerPublicRoutes(rg)
+
+	// update counter views artikel
+	rg.GET("/articles/:slug/views", rateLimit("views"), controller.IncrementArticleViews)
+
+	admin := rg.Group("/admin")
+	admin.Use(middlewares.AuthMiddleware(), rateLimit("admin"), middlewares.AuditMiddleware())
+	registerAdminRoutes(admin)
+}
+
+func registerAuthRoutes(rg *gin.RouterGroup) {
+	rg.POST("/login", rateLimit("login"), controller.Login)
+	rg.POST("/login/2fa", rateLimit("login_2fa"), controller.LoginTwoFactor)
+	rg.POST("/create-user", rateLimit("register"), middlewares.AuditMiddleware(), controller.CreateUser)
+	rg.POST("/password/forgot", rateLimit("password_forgot"), controller.ForgotPassword)
+	rg.POST("/password/reset", rateLimit("password_reset"), controller.ResetPassword)
+}
+
+func registerPublicRoutes(rg *gin.RouterGroup) {
+	rg = rg.Group("", rateLimit("public"))
+	rg.GET("/pages", controller.GetAllPages)
+	rg.GET("/abouts", controller.GetAllAbout)
+	rg.GET("/services", cached("services"), controller.GetAllServices)
+	rg.GET("/services/:slug", cached("services"), controller.GetServiceBySlug)
+	rg.GET("/portfolios", controller.GetAllPortfolio)
+	rg.GET("/products", controller.GetAllProduct)
+	rg.GET("/contacts", controller.GetAllContact)
+	rg.GET("/users", controller.GetUserNotAdmin)
+	rg.GET("/authors/:slug", controller.GetAuthorBySlug)
+	rg.GET("/authors/:slug/articles", controller.GetAuthorArticles)
+	rg.GET("/category-articles", controller.GetAllCategoryArticle)
+	rg.GET("/articles", cached("articles"), controller.GetAllArticle)
+	rg.GET("/articles/:slug", cached("articles"), controller.GetArticleBySlug)
+	rg.GET("/category-faqs", controller.GetAllCategoryFaq)
+	rg.GET("/faqs", cached("faqs"), controller.GetAllFaq)
+}
+
+func registerAdminRoutes(protected *gin.RouterGroup) {
+	// route pages
+	protected.GET("/pages", controller.GetAllPages)
+	protected.POST("/pages", controller.CreatePage)
+	protected.PUT("/pages/:id", controller.UpdatePage)
+	protected.DELETE("/pages/:id", controller.DeletePage)
+
+	// route about
+	protected.GET("/abouts", controller.GetAllAbout)
+	protected.POST("/abouts", controller.CreateAbout)
+	protected.PUT("/abouts/:id", controller.UpdateAbout)
+	protected.DELETE("/abouts/:id", controller.DeleteAbout)
+
+	// route services
+	protected.GET("/services", controller.GetAllServices)
+	protected.POST("/services", controller.CreateService)
+	protected.PUT("/services/:id", controller.UpdateService)
+	protected.DELETE("/services/:id", controller.DeleteService)
+
+	// route portfolios
+	protected.GET("/portfolios", controller.GetAllPortfolio)
+	protected.POST("/portfolios", controller.CreatePortfolio)
+	protected.PUT("/portfolios/:id", controller.UpdatePortfolio)
+	protected.DELETE("/portfolios/:id", controller.DeletePortfolio)
+
+	// route products
+	protected.GET("/products", controller.GetAllProduct)
+	protected.POST("/products", controller.CreateProduct)
+	protected.PUT("/products/:id", controller.UpdateProduct)
+	protected.DELETE("/products/:id", controller.DeleteProduct)
+
+	// route contacts
+	protected.GET("/contacts", controller.GetAllContact)
+	protected.POST("/contacts", controller.CreateContact)
+	protected.PUT("/contacts/:id", controller.UpdateContact)
+	protected.DELETE("/contacts/:id", controller.DeleteContact)
+
+	// route users
+	protected.GET("/users", controller.GetAllUser)
+	protected.POST("/users", controller.CreateUser)
+	protected.PUT("/users/:id", controller.UpdateUser)
+	protected.DELETE("/users/:id", controller.DeleteUser)
+	protected.POST("/users/:id/unlock", controller.UnlockUser)
+	protected.DELETE("/users/:id/2fa", controller.ResetUserTwoFactor)
+	// get user by is login
+	protected.GET("/users/me", controller.GetUser)
+	protected.PUT("/users/me", controller.UpdateMe)
+	protected.POST("/users/me/password", controller.ChangePassword)
+	// profil publik penulis
+	protected.PUT("/users/:id/author-profile", controller.UpdateAuthorProfile)
+
+	// route category faq
+	protected.GET("/category-faqs", controller.GetAllCategoryFaq)
+	protected.POST("/category-faqs", controller.CreateCategoryFaq)
+	protected.PUT("/category-faqs/:id", controller.UpdateCategoryFaq)
+	protected.DELETE("/category-faqs/:id", controller.DeleteCategoryFaq)
+
+	// route faq
+	protected.GET("/faqs", controller.GetAllFaq)
+	protected.POST("/faqs", controller.CreateFaq)
+	protected.PUT("/faqs/:id", controller.UpdateFaq)
+	protected.DELETE("/faqs/:id", controller.DeleteFaq)
+
+	// route category articles
+	protected.GET("/category-articles", controller.GetAllCategoryArticle)
+	protected.POST("/category-articles", controller.CreateCategoryArticle)
+	protected.PUT("/category-articles/:id", controller.UpdateCategoryArticle)
+	protected.DELETE("/category-articles/:id", controller.DeleteCategoryArticle)
+
+	// route articles
+	protected.GET("/articles", controller.GetAllArticleAdmin)
+	protected.POST("/articles", controller.CreateArticle)
+	protected.PUT("/articles/:id", controller.UpdateArticle)
+	protected.DELETE("/articles/:id", controller.DeleteArticle)
+	protected.POST("/articles/images", controller.UploadArticleImage)
+
+	// route two-factor authentication
+	protected.POST("/2fa/setup", controller.SetupTwoFactor)
+	protected.POST("/2fa/verify", controller.VerifyTwoFactor)
+	protected.POST("/2fa/disable", controller.DisableTwoFactor)
+
+	// route api keys
+	protected.GET("/api-keys", controller.GetAllApiKey)
+	protected.POST("/api-keys", controller.CreateApiKey)
+	protected.DELETE("/api-keys/:id", controller.RevokeApiKey)
+
+	// route audit logs
+	protected.GET("/audit-logs", controller.GetAllAuditLog)
+}
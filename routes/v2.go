@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gibranfajar/backend-codetech/controller"
+	"github.com/gibranfajar/backend-codetech/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+// daftarkan route API v2, perubahan yang tidak kompatibel dengan v1 ditaruh di sini
+func RegisterV2(rg *gin.RouterGroup) {
+	registerAuthRoutes(rg)
+	registerPublicRoutes(rg)
+
+	// counter views mengubah data, di v2 memakai POST
+	rg.POST("/articles/:slug/views", rateLimit("views"), controller.IncrementArticleViews)
+
+	admin := rg.Group("/admin")
+	admin.Use(middlewares.AuthMiddleware(), rateLimit("admin"), middlewares.AuditMiddleware())
+	registerAdminRoutes(admin)
+}
@@ -0,0 +1,58 @@
+package seo
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gibranfajar/backend-codetech/content"
+	"github.com/gibranfajar/backend-codetech/model"
+)
+
+// pola url publik per jenis konten, bisa diganti lewat env SEO_URL_<KIND>
+var defaultPatterns = map[string]string{
+	"article":  "/articles/{slug}",
+	"page":     "/{slug}",
+	"service":  "/services/{slug}",
+	"category": "/articles/category/{slug}",
+}
+
+// url situs publik (frontend), bukan url API
+func SiteURL() string {
+	return strings.TrimRight(config.GetEnv("SITE_URL", "https://codetech.crx.my.id"), "/")
+}
+
+// url absolut konten sesuai pola, contoh URL("article", "belajar-go")
+func URL(kind, slug string) string {
+	pattern := config.GetEnv("SEO_URL_"+strings.ToUpper(kind), defaultPatterns[kind])
+	return SiteURL() + strings.ReplaceAll(pattern, "{slug}", url.PathEscape(slug))
+}
+
+// path upload (/uploads/...) dijadikan url absolut, url yang sudah absolut dibiarkan
+func MediaURL(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimRight(config.GetEnv("MEDIA_URL", SiteURL()), "/") + path
+}
+
+// isi field SEO yang kosong dengan default dari konten
+func Resolve(meta model.Seo, kind, slug, title, description, image string) model.Seo {
+	if meta.MetaTitle == "" {
+		meta.MetaTitle = title
+	}
+	if meta.MetaDescription == "" {
+		meta.MetaDescription = content.Excerpt(description, 160)
+	}
+	if meta.CanonicalUrl == "" {
+		meta.CanonicalUrl = URL(kind, slug)
+	}
+	if meta.OgImage == "" {
+		meta.OgImage = image
+	}
+	meta.OgImage = MediaURL(meta.OgImage)
+	if meta.Robots == "" {
+		meta.Robots = config.GetEnv("SEO_DEFAULT_ROBOTS", "index, follow")
+	}
+	return meta
+}
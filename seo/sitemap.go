@@ -0,0 +1,125 @@
+package seo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+// batas url per file sitemap sesuai protokol sitemaps.org
+const MaxSitemapURLs = 50000
+
+// satu url di sitemap
+type Entry struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	URLs    []location `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	Xmlns    string     `xml:"xmlns,attr"`
+	Sitemaps []location `xml:"sitemap"`
+}
+
+type location struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+const sitemapXmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// url publik tempat sitemap disajikan, dipakai di sitemap index dan robots.txt
+func SitemapBaseURL() string {
+	return strings.TrimRight(config.GetEnv("SITEMAP_BASE_URL", SiteURL()), "/")
+}
+
+// jumlah file sitemap yang dibutuhkan, 1 berarti cukup satu urlset tanpa index
+func SitemapPages(total int) int {
+	if total <= MaxSitemapURLs {
+		return 1
+	}
+	return (total + MaxSitemapURLs - 1) / MaxSitemapURLs
+}
+
+// urlset untuk halaman ke-page (mulai 1)
+func URLSet(entries []Entry, page int) ([]byte, bool) {
+	if page < 1 || page > SitemapPages(len(entries)) {
+		return nil, false
+	}
+	start := (page - 1) * MaxSitemapURLs
+	end := min(start+MaxSitemapURLs, len(entries))
+
+	set := urlSet{Xmlns: sitemapXmlns, URLs: []location{}}
+	for _, entry := range entries[start:end] {
+		set.URLs = append(set.URLs, location{Loc: entry.Loc, LastMod: lastMod(entry.LastMod)})
+	}
+	return marshalXML(set), true
+}
+
+// sitemap index yang menunjuk ke /sitemaps/<n>.xml, lastmod diambil dari entry terbaru tiap file
+func Index(entries []Entry) []byte {
+	index := sitemapIndex{Xmlns: sitemapXmlns}
+	for page := 1; page <= SitemapPages(len(entries)); page++ {
+		var latest time.Time
+		for _, entry := range entries[(page-1)*MaxSitemapURLs : min(page*MaxSitemapURLs, len(entries))] {
+			if entry.LastMod.After(latest) {
+				latest = entry.LastMod
+			}
+		}
+		index.Sitemaps = append(index.Sitemaps, location{
+			Loc:     fmt.Sprintf("%s/sitemaps/%d.xml", SitemapBaseURL(), page),
+			LastMod: lastMod(latest),
+		})
+	}
+	return marshalXML(index)
+}
+
+// isi robots.txt dari env ROBOTS_DISALLOW, ROBOTS_ALLOW dan ROBOTS_NOINDEX (untuk staging)
+func RobotsTxt() []byte {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if config.GetEnvBool("ROBOTS_NOINDEX", false) {
+		b.WriteString("Disallow: /\n")
+		return []byte(b.String())
+	}
+
+	for _, path := range splitPaths(config.GetEnv("ROBOTS_ALLOW", "/")) {
+		b.WriteString("Allow: " + path + "\n")
+	}
+	for _, path := range splitPaths(config.GetEnv("ROBOTS_DISALLOW", "/admin")) {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + SitemapBaseURL() + "/sitemap.xml\n")
+	return []byte(b.String())
+}
+
+func splitPaths(value string) []string {
+	paths := []string{}
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func lastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func marshalXML(v interface{}) []byte {
+	out, _ := xml.MarshalIndent(v, "", "  ")
+	return append([]byte(xml.Header), out...)
+}
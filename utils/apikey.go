@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+)
+
+// prefix api key, dipakai AuthMiddleware untuk membedakan dengan JWT
+const ApiKeyPrefix = "ctk_"
+
+// resource yang tidak boleh diakses dengan api key sama sekali
+var apiKeyForbiddenResources = map[string]bool{
+	"api-keys": true,
+	"2fa":      true,
+	"me":       true,
+}
+
+// nama resource dari route, contoh /api/admin/articles/:id -> articles
+func RouteResource(fullPath string) string {
+	for _, segment := range strings.Split(strings.Trim(fullPath, "/"), "/") {
+		if segment == "api" || segment == "admin" || segment == "" || strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if len(segment) > 1 && segment[0] == 'v' && strings.Trim(segment[1:], "0123456789") == "" {
+			continue
+		}
+		return segment
+	}
+	return ""
+}
+
+// scope yang valid: *, read, <resource>:read, <resource>:write
+func ValidApiKeyScope(scope string) bool {
+	if scope == "*" || scope == "read" {
+		return true
+	}
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || (access != "read" && access != "write") {
+		return false
+	}
+	if apiKeyForbiddenResources[resource] {
+		return false
+	}
+	_, known := AuditTables[resource]
+	return known || resource == "audit-logs"
+}
+
+// cek apakah scope mengizinkan method + route ini
+func ApiKeyAllows(scopes []string, method, fullPath string) bool {
+	resource := RouteResource(fullPath)
+	if apiKeyForbiddenResources[resource] {
+		return false
+	}
+
+	// sub-resource users/me/... juga diblokir
+	if strings.Contains(fullPath, "/me/") || strings.HasSuffix(fullPath, "/me") {
+		return false
+	}
+
+	readOnly := method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+	for _, scope := range scopes {
+		switch scope {
+		case "*":
+			return true
+		case "read":
+			if readOnly {
+				return true
+			}
+		case resource + ":write":
+			return true
+		case resource + ":read":
+			if readOnly {
+				return true
+			}
+		}
+	}
+	return false
+}
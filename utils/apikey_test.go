@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestApiKeyAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []string
+		method string
+		path   string
+		want   bool
+	}{
+		{"wildcard write", []string{"*"}, http.MethodPost, "/api/admin/articles", true},
+		{"global read on GET", []string{"read"}, http.MethodGet, "/api/admin/articles", true},
+		{"global read on HEAD", []string{"read"}, http.MethodHead, "/api/admin/pages", true},
+		{"global read on POST", []string{"read"}, http.MethodPost, "/api/admin/articles", false},
+		{"resource read on GET", []string{"articles:read"}, http.MethodGet, "/api/admin/articles", true},
+		{"resource read on PUT", []string{"articles:read"}, http.MethodPut, "/api/admin/articles/:id", false},
+		{"resource write on DELETE", []string{"articles:write"}, http.MethodDelete, "/api/admin/articles/:id", true},
+		{"other resource", []string{"pages:write"}, http.MethodPost, "/api/admin/articles", false},
+		{"versioned path", []string{"articles:write"}, http.MethodPost, "/api/v1/admin/articles", true},
+		{"api keys blocked even with wildcard", []string{"*"}, http.MethodGet, "/api/admin/api-keys", false},
+		{"2fa blocked", []string{"*"}, http.MethodPost, "/api/admin/2fa/setup", false},
+		{"own profile blocked", []string{"*"}, http.MethodGet, "/api/admin/users/me", false},
+		{"own password blocked", []string{"users:write"}, http.MethodPost, "/api/admin/users/me/password", false},
+		{"no scopes", nil, http.MethodGet, "/api/admin/articles", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ApiKeyAllows(tt.scopes, tt.method, tt.path); got != tt.want {
+				t.Errorf("ApiKeyAllows(%v, %s, %s) = %v, want %v", tt.scopes, tt.method, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidApiKeyScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  bool
+	}{
+		{"*", true},
+		{"read", true},
+		{"articles:read", true},
+		{"articles:write", true},
+		{"audit-logs:read", true},
+		{"articles:delete", false},
+		{"api-keys:write", false},
+		{"me:read", false},
+		{"unknown:read", false},
+		{"write", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidApiKeyScope(tt.scope); got != tt.want {
+			t.Errorf("ValidApiKeyScope(%q) = %v, want %v", tt.scope, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/gin-gonic/gin"
+)
+
+// tabel yang boleh di-snapshot oleh audit log (key = nama resource di url)
+var AuditTables = map[string]string{
+	"pages":             "pages",
+	"abouts":            "abouts",
+	"services":          "services",
+	"portfolios":        "portfolios",
+	"products":          "products",
+	"contacts":          "contacts",
+	"users":             "users",
+	"create-user":       "users",
+	"category-faqs":     "category_faqs",
+	"faqs":              "faqs",
+	"category-articles": "category_articles",
+	"articles":          "articles",
+	"author-profile":    "author_profiles",
+	"api-keys":          "api_keys",
+}
+
+// kolom primary key jika bukan id
+var auditKeys = map[string]string{
+	"author_profiles": "user_id",
+}
+
+// kolom yang tidak boleh ikut tersimpan di audit log
+var auditSensitiveFields = []string{"password", "key_hash", "secret"}
+
+// ambil isi baris sebagai json untuk before / after
+func AuditSnapshot(ctx context.Context, table string, id int) json.RawMessage {
+	key := "id"
+	if k, ok := auditKeys[table]; ok {
+		key = k
+	}
+
+	var raw []byte
+	err := config.DB.QueryRowContext(ctx, "SELECT row_to_json(t) FROM "+table+" t WHERE t."+key+" = $1", id).Scan(&raw)
+	if err != nil {
+		return nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil
+	}
+	for _, field := range auditSensitiveFields {
+		delete(data, field)
+	}
+
+	cleaned, err := json.Marshal(data)
+	if err != nil {
+		return nil
+	}
+	return cleaned
+}
+
+// simpan satu baris audit log, actor diambil dari user_id di context
+func RecordAudit(c *gin.Context, action, resourceType string, resourceId *int, before, after json.RawMessage) {
+	// audit tetap dicatat walau client sudah memutus koneksi
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+	defer cancel()
+
+	var actorId *int
+	if id, ok := c.Get("user_id"); ok {
+		if v, ok := id.(int); ok {
+			actorId = &v
+		}
+	}
+
+	_, err := config.DB.ExecContext(ctx, `
+		INSERT INTO audit_logs (actor_id, action, resource_type, resource_id, before, after, ip, user_agent, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, actorId, action, resourceType, resourceId, nullableJSON(before), nullableJSON(after), c.ClientIP(), c.Request.UserAgent(), time.Now())
+
+	if err != nil {
+		Logger(c).Error("Failed to write audit log", "error", err)
+	}
+}
+
+func nullableJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return []byte(data)
+}
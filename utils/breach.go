@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+var breachClient = &http.Client{}
+
+// cek password ke Have I Been Pwned dengan k-anonymity: hanya 5 karakter awal hash SHA-1 yang dikirim,
+// sisa hash dicocokkan di sini. Opt-in lewat PASSWORD_BREACH_CHECK=true, defaultnya cukup daftar lokal.
+// Jangan dipanggil selama transaksi DB terbuka karena bisa menunggu sampai PASSWORD_BREACH_TIMEOUT
+func IsBreachedPassword(ctx context.Context, password string) (bool, error) {
+	if !config.GetEnvBool("PASSWORD_BREACH_CHECK", false) {
+		return false, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	ctx, cancel := context.WithTimeout(ctx, config.GetEnvDuration("PASSWORD_BREACH_TIMEOUT", 3*time.Second))
+	defer cancel()
+
+	url := strings.TrimRight(config.GetEnv("PASSWORD_BREACH_API_URL", "https://api.pwnedpasswords.com/range"), "/") + "/" + prefix
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	// padding agar ukuran response tidak membocorkan prefix yang diminta
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "codetech-backend")
+
+	resp, err := breachClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach api status %d", resp.StatusCode)
+	}
+
+	// format baris: SUFFIX:COUNT, baris padding punya count 0
+	minCount := config.GetEnvInt("PASSWORD_BREACH_MIN_COUNT", 1)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		return err == nil && n >= minCount, nil
+	}
+	return false, scanner.Err()
+}
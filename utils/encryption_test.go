@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+func TestEncryptSecret(t *testing.T) {
+	config.EncryptionKey = bytes.Repeat([]byte{7}, 32)
+
+	stored, err := EncryptSecret(rfcTOTPSecret)
+	if err != nil {
+		t.Fatalf("EncryptSecret: %v", err)
+	}
+	if !strings.HasPrefix(stored, encryptedPrefix) || strings.Contains(stored, rfcTOTPSecret) {
+		t.Fatalf("EncryptSecret = %q, want encrypted value", stored)
+	}
+
+	plain, err := DecryptSecret(stored)
+	if err != nil || plain != rfcTOTPSecret {
+		t.Fatalf("DecryptSecret = %q, %v, want %q", plain, err, rfcTOTPSecret)
+	}
+
+	// secret lama yang belum terenkripsi tetap terbaca
+	if plain, err := DecryptSecret(rfcTOTPSecret); err != nil || plain != rfcTOTPSecret {
+		t.Errorf("DecryptSecret(plain) = %q, %v", plain, err)
+	}
+
+	// key lain tidak bisa membuka
+	config.EncryptionKey = bytes.Repeat([]byte{8}, 32)
+	if _, err := DecryptSecret(stored); err == nil {
+		t.Error("DecryptSecret with wrong key succeeded")
+	}
+}
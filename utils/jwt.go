@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/gibranfajar/backend-codetech/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func signingMethod(key *config.JWTKey) jwt.SigningMethod {
+	if _, ok := key.PublicKey.(ed25519.PublicKey); ok {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// sign token dengan key aktif, kid dan iss ditambahkan otomatis
+func SignToken(claims jwt.MapClaims) (string, error) {
+	key := config.JWTSigningKey
+	claims["iss"] = config.JWTIssuer
+
+	token := jwt.NewWithClaims(signingMethod(key), claims)
+	token.Header["kid"] = key.Id
+
+	return token.SignedString(key.PrivateKey)
+}
+
+// parse token, key dipilih berdasarkan kid sehingga key lama tetap bisa diverifikasi
+func ParseToken(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := config.JWTVerificationKeys[kid]
+		if !ok {
+			return nil, errors.New("unknown kid")
+		}
+
+		// validasi metode signing sesuai tipe key
+		if token.Method.Alg() != signingMethod(key).Alg() {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"RS256", "EdDSA"}), jwt.WithIssuer(config.JWTIssuer), jwt.WithExpirationRequired())
+}
+
+// public key dalam format JWK untuk /.well-known/jwks.json
+func JWKS() []map[string]string {
+	kids := []string{}
+	for kid := range config.JWTVerificationKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := []map[string]string{}
+	for _, kid := range kids {
+		key := config.JWTVerificationKeys[kid]
+		switch public := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": key.Id,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"kid": key.Id,
+				"use": "sig",
+				"alg": "EdDSA",
+				"x":   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	return keys
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// key logger per request di gin context
+const LoggerKey = "logger"
+
+// logger request saat ini, sudah membawa request_id, route dan user_id
+func Logger(c *gin.Context) *slog.Logger {
+	logger := slog.Default()
+	if value, ok := c.Get(LoggerKey); ok {
+		if l, ok := value.(*slog.Logger); ok {
+			logger = l
+		}
+	}
+
+	if route := c.FullPath(); route != "" {
+		logger = logger.With("route", route)
+	}
+	if userId, ok := c.Get("user_id"); ok {
+		logger = logger.With("user_id", userId)
+	}
+	return logger
+}
+
+// response 500 tanpa detail error, error asli dicatat di log server
+func ServerError(c *gin.Context, message string, err error) {
+	if err != nil {
+		c.Error(err)
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
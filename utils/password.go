@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	_ "embed"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gibranfajar/backend-codetech/config"
+)
+
+// daftar password umum yang dibundel sebagai fallback offline untuk IsBreachedPassword,
+// bisa ditambah lewat PASSWORD_BLOCKLIST_FILE (contoh daftar top 100k)
+//
+//go:embed common_passwords.txt
+var bundledCommonPasswords []byte
+
+var (
+	commonPasswords     map[string]bool
+	commonPasswordsOnce sync.Once
+)
+
+type PasswordPolicy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	HistorySize   int
+}
+
+// policy diambil dari env, default: minimal 8 karakter, huruf kecil, angka, tidak boleh sama dengan 5 password terakhir
+func LoadPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     config.GetEnvInt("PASSWORD_MIN_LENGTH", 8),
+		RequireUpper:  config.GetEnvBool("PASSWORD_REQUIRE_UPPER", false),
+		RequireLower:  config.GetEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		RequireDigit:  config.GetEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol: config.GetEnvBool("PASSWORD_REQUIRE_SYMBOL", false),
+		HistorySize:   config.GetEnvInt("PASSWORD_HISTORY", 5),
+	}
+}
+
+// cek password terhadap policy, personal berisi email / nama user yang tidak boleh dipakai
+func ValidatePassword(ctx context.Context, password string, personal ...string) []string {
+	policy := LoadPasswordPolicy()
+	errors := []string{}
+
+	if len([]rune(password)) < policy.MinLength {
+		errors = append(errors, "Password must be at least "+strconv.Itoa(policy.MinLength)+" characters")
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if policy.RequireUpper && !hasUpper {
+		errors = append(errors, "Password must contain an uppercase letter")
+	}
+	if policy.RequireLower && !hasLower {
+		errors = append(errors, "Password must contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		errors = append(errors, "Password must contain a number")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		errors = append(errors, "Password must contain a symbol")
+	}
+
+	if containsPersonalInfo(password, personal) {
+		errors = append(errors, "Password must not contain your name or email")
+	}
+
+	if IsCommonPassword(password) || isBreached(ctx, password) {
+		errors = append(errors, "Password is too common or has appeared in a data breach")
+	}
+
+	return errors
+}
+
+// API breach tidak bisa dihubungi, cukup pakai daftar lokal (IsCommonPassword)
+func isBreached(ctx context.Context, password string) bool {
+	breached, err := IsBreachedPassword(ctx, password)
+	if err != nil {
+		slog.WarnContext(ctx, "Password breach check failed, using local list only", "error", err)
+		return false
+	}
+	return breached
+}
+
+// password tidak boleh mengandung email, bagian depan email, atau kata dari nama (>= 3 huruf)
+func containsPersonalInfo(password string, personal []string) bool {
+	lower := strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		parts := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if at := strings.Index(value, "@"); at > 0 {
+			parts = append(parts, value, value[:at])
+		}
+
+		for _, part := range parts {
+			if len(part) >= 3 && strings.Contains(lower, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func IsCommonPassword(password string) bool {
+	commonPasswordsOnce.Do(loadCommonPasswords)
+	return commonPasswords[strings.ToLower(password)]
+}
+
+func loadCommonPasswords() {
+	commonPasswords = map[string]bool{}
+	readPasswordList(bytes.NewReader(bundledCommonPasswords))
+
+	if path := config.GetEnv("PASSWORD_BLOCKLIST_FILE", ""); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			slog.Error("Failed to open password blocklist", "error", err)
+			return
+		}
+		defer file.Close()
+		readPasswordList(file)
+	}
+}
+
+func readPasswordList(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			commonPasswords[strings.ToLower(line)] = true
+		}
+	}
+}
+
+// cek apakah password sama dengan password sekarang atau N password terakhir
+func IsPasswordReused(ctx context.Context, userId int, password string) (bool, error) {
+	var currentHash string
+	err := config.DB.QueryRowContext(ctx, "SELECT password FROM users WHERE id = $1", userId).Scan(&currentHash)
+	if err != nil {
+		return false, err
+	}
+	if CheckPasswordHash(password, currentHash) {
+		return true, nil
+	}
+
+	historySize := LoadPasswordPolicy().HistorySize
+	if historySize <= 0 {
+		return false, nil
+	}
+
+	rows, err := config.DB.QueryContext(ctx, `
+		SELECT password_hash FROM password_histories
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`, userId, historySize)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return false, err
+		}
+		if CheckPasswordHash(password, hash) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// *sql.DB dan *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// simpan hash password lama ke history sebelum diganti, history lebih dari N dihapus
+func SavePasswordHistory(ctx context.Context, db Execer, userId int) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO password_histories (user_id, password_hash, created_at)
+		SELECT id, password, NOW() FROM users WHERE id = $1
+	`, userId)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, `
+		DELETE FROM password_histories
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_histories WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2
+		)
+	`, userId, LoadPasswordPolicy().HistorySize)
+	return err
+}
@@ -0,0 +1,32 @@
+package utils
+
+import "github.com/gin-gonic/gin"
+
+// role yang boleh mengubah data sensitif (atribusi artikel, role user, dll)
+var PrivilegedRoles = []string{"superadmin", "admin"}
+
+func IsPrivilegedRole(role string) bool {
+	for _, r := range PrivilegedRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// cek role user yang sedang login (di-set oleh AuthMiddleware)
+func IsPrivileged(c *gin.Context) bool {
+	return IsPrivilegedRole(c.GetString("role"))
+}
+
+// tingkatan role, role yang tidak dikenal setara user biasa
+var roleRanks = map[string]int{"superadmin": 2, "admin": 1}
+
+func RoleRank(role string) int {
+	return roleRanks[role]
+}
+
+// role hanya boleh diberikan oleh role privileged yang tingkatannya tidak lebih rendah
+func CanAssignRole(assigner, role string) bool {
+	return IsPrivilegedRole(assigner) && RoleRank(role) <= RoleRank(assigner)
+}
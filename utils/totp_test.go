@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// secret ASCII "12345678901234567890" dari RFC 6238 dalam base32
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// vektor uji SHA1 RFC 6238 Appendix B, 6 digit terakhir
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := TOTPCode(rfcTOTPSecret, TOTPStep(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("TOTPCode(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestTOTPCodeInvalidSecret(t *testing.T) {
+	if _, err := TOTPCode("not base32!", 1); err == nil {
+		t.Fatal("expected error for invalid secret")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := TOTPStep(now)
+	codeAt := func(s int64) string {
+		code, err := TOTPCode(rfcTOTPSecret, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOk   bool
+	}{
+		{"current step", codeAt(step), 0, step, true},
+		{"previous step within skew", codeAt(step - 1), 0, step - 1, true},
+		{"next step within skew", codeAt(step + 1), 0, step + 1, true},
+		{"outside skew", codeAt(step - 2), 0, 0, false},
+		{"already used step", codeAt(step), step, 0, false},
+		{"surrounded by spaces", " " + codeAt(step) + " ", 0, step, true},
+		{"wrong length", codeAt(step)[:5], 0, 0, false},
+		{"wrong code", "000000", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := ValidateTOTP(rfcTOTPSecret, tt.code, now, tt.lastStep)
+			if ok != tt.wantOk || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q) = (%d, %v), want (%d, %v)", tt.code, gotStep, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := TOTPCode(secret, 1); err != nil {
+		t.Errorf("generated secret %q is not valid base32: %v", secret, err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32 (160 bit)", len(secret))
+	}
+}
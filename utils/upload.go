@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"mime/multipart"
+	"os"
+
+	"github.com/gibranfajar/backend-codetech/metrics"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/gibranfajar/backend-codetech/utils")
+
+// simpan file upload ke disk dan catat metrik upload per resource
+func SaveUpload(c *gin.Context, file *multipart.FileHeader, path string) error {
+	_, span := tracer.Start(c.Request.Context(), "file.save", trace.WithAttributes(
+		attribute.String("file.path", path),
+		attribute.Int64("file.size", file.Size),
+	))
+	defer span.End()
+
+	if err := c.SaveUploadedFile(file, path); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "save failed")
+		return err
+	}
+
+	resource := RouteResource(c.FullPath())
+	if resource == "" {
+		resource = "unknown"
+	}
+	metrics.ObserveUpload(resource, file.Size)
+	return nil
+}
+
+// hapus file upload lama
+func RemoveUpload(c *gin.Context, path string) error {
+	_, span := tracer.Start(c.Request.Context(), "file.delete", trace.WithAttributes(
+		attribute.String("file.path", path),
+	))
+	defer span.End()
+
+	if err := os.Remove(path); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete failed")
+		return err
+	}
+	return nil
+}